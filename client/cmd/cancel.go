@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel <run id>",
+	Short: "Cancel a queued or running run",
+	Long: `Cancel a queued or running run. A run that is executing has its container stopped and removed by its worker`,
+	Args: cobra.ExactArgs(1),
+	Run : cancelRun,
+}
+
+func init(){
+	rootCmd.AddCommand(cancelCmd)
+}
+
+func cancelRun(cmd *cobra.Command, args []string) {
+	manageJob(func(ctx context.Context, client pb.SchedulerClient) (*pb.JobResponse, error) {
+		return client.CancelRun(ctx, &pb.CancelRunRequest{RunId: args[0]})
+	})
+}
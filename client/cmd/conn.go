@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"log"
+	"os"
+
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// connect opens an mTLS connection to the server using the global flags from root.go.
+// The caller closes the returned connection
+func connect() (*grpc.ClientConn, pb.SchedulerClient) {
+	// Generate the certificate from the pem blocks
+	cert, err := tls.LoadX509KeyPair(cert, key) // Gets the cert and key from global flags from root.go
+	if err != nil{
+		log.Fatalf("[-] Error reading certificates %v", err)
+	}
+
+	// Root cert
+	caCert, err := os.ReadFile(caCert)
+	if err != nil{
+		log.Printf("[-] Error loading server certificate %v", err)
+	}
+
+	// Create a cert pool and add the root ca to it
+	caCertPool := x509.NewCertPool()
+	if ok := caCertPool.AppendCertsFromPEM(caCert); !ok { // Gets the caCert from global flags
+        log.Fatalln("[-] Could not append cert to pool")
+    }
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs: caCertPool, // The Server used ClientCAs to verify incoming clients. The Client/Worker uses RootCAs to verify the destination server.
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))) // Gets the target from global flags
+	if err != nil{
+		log.Fatalf("[-] Error connecting to server: %v", err)
+	}
+	return conn, pb.NewSchedulerClient(conn)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var cordonCmd = &cobra.Command{
+	Use:   "cordon <worker id>",
+	Short: "Stop handing new runs to a worker",
+	Long: `Stop handing new runs to a worker, also after it reconnects, until it is uncordoned. Runs it holds are left to finish`,
+	Args: cobra.ExactArgs(1),
+	Run : cordonWorker,
+}
+
+var uncordonCmd = &cobra.Command{
+	Use:   "uncordon <worker id>",
+	Short: "Let a cordoned worker take runs again",
+	Args: cobra.ExactArgs(1),
+	Run : uncordonWorker,
+}
+
+var drainCmd = &cobra.Command{
+	Use:   "drain <worker id>",
+	Short: "Cordon a worker and disconnect it once its runs finish",
+	Long: `Cordon a worker and, once the runs it holds have finished, have it disconnect and exit. It stays cordoned when it comes back, until it is uncordoned`,
+	Args: cobra.ExactArgs(1),
+	Run : drainWorker,
+}
+
+func init(){
+	rootCmd.AddCommand(cordonCmd)
+	rootCmd.AddCommand(uncordonCmd)
+	rootCmd.AddCommand(drainCmd)
+}
+
+func cordonWorker(cmd *cobra.Command, args []string) {
+	manageWorker(func(ctx context.Context, client pb.SchedulerClient) (*pb.Worker, error) {
+		return client.CordonWorker(ctx, &pb.CordonWorkerRequest{WorkerId: args[0]})
+	})
+}
+
+func uncordonWorker(cmd *cobra.Command, args []string) {
+	manageWorker(func(ctx context.Context, client pb.SchedulerClient) (*pb.Worker, error) {
+		return client.UncordonWorker(ctx, &pb.UncordonWorkerRequest{WorkerId: args[0]})
+	})
+}
+
+func drainWorker(cmd *cobra.Command, args []string) {
+	manageWorker(func(ctx context.Context, client pb.SchedulerClient) (*pb.Worker, error) {
+		return client.DrainWorker(ctx, &pb.DrainWorkerRequest{WorkerId: args[0]})
+	})
+}
+
+// manageWorker makes a call that changes a worker and prints its new state
+func manageWorker(call func(ctx context.Context, client pb.SchedulerClient) (*pb.Worker, error)) {
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	worker, err := call(ctx, client)
+	if err != nil{
+		log.Fatalf("[-] %v", err)
+	}
+	log.Printf("[+] Worker %s is %s, holding %d runs", worker.WorkerId, workerState(worker), len(worker.RunIds))
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var deadLetters = &cobra.Command{
+	Use:   "dead-letters [-j <job id>] | dead-letters --run-id <run id>",
+	Short: "List runs that failed for good, or show one",
+	Long: `List the runs that failed after using up their retries, or show one of them including its last output`,
+	Run : listDeadLetters,
+}
+
+var requeueCmd = &cobra.Command{
+	Use:   "requeue <run id> | requeue -j <job id> | requeue --all",
+	Short: "Queue dead-lettered runs again",
+	Long: `Queue dead-lettered runs again, with the current definition of their job and a fresh retry budget`,
+	Args: cobra.MaximumNArgs(1),
+	Run : requeueDeadLetters,
+}
+
+func init(){
+	rootCmd.AddCommand(deadLetters)
+	rootCmd.AddCommand(requeueCmd)
+
+	deadLetters.Flags().StringP("job-id", "j", "", "Only dead letters of this job")
+	deadLetters.Flags().String("run-id", "", "Run id, shows that dead letter and its output")
+	deadLetters.Flags().Int32P("limit", "n", 50, "Max dead letters to list")
+
+	requeueCmd.Flags().StringP("job-id", "j", "", "Requeue every dead letter of this job")
+	requeueCmd.Flags().Bool("all", false, "Requeue every dead letter")
+}
+
+func listDeadLetters(cmd *cobra.Command, args []string) {
+	jobId, _ := cmd.Flags().GetString("job-id")
+	runId, _ := cmd.Flags().GetString("run-id")
+	limit, _ := cmd.Flags().GetInt32("limit")
+
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if runId != "" {
+		dl, err := client.GetDeadLetter(ctx, &pb.GetDeadLetterRequest{RunId: runId})
+		if err != nil{
+			log.Fatalf("[-] Error getting dead letter: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Run:\t%s\nJob:\t%s\nImage:\t%s\nCommand:\t%s\n", dl.RunId, dl.JobId, dl.Image, dl.Command)
+		fmt.Fprintf(w, "Reason:\t%s\nAttempts:\t%d\nExit code:\t%s\n", dl.Reason, dl.Attempts, formatExitCode(dl.ExitCode))
+		fmt.Fprintf(w, "Scheduled:\t%s\nFailed:\t%s\n", formatUnix(dl.ScheduledAt), formatUnix(dl.FailedAt))
+		w.Flush()
+		fmt.Printf("Output:\n%s\n", dl.Output)
+		return
+	}
+
+	resp, err := client.ListDeadLetters(ctx, &pb.ListDeadLettersRequest{JobId: jobId, Limit: limit})
+	if err != nil{
+		log.Fatalf("[-] Error listing dead letters: %v", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN ID\tIMAGE\tATTEMPTS\tEXIT\tFAILED\tREASON")
+	for _, dl := range resp.DeadLetters {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", dl.RunId, dl.Image, dl.Attempts,
+			formatExitCode(dl.ExitCode), formatUnix(dl.FailedAt), dl.Reason)
+	}
+	w.Flush()
+}
+
+func requeueDeadLetters(cmd *cobra.Command, args []string) {
+	jobId, _ := cmd.Flags().GetString("job-id")
+	all, _ := cmd.Flags().GetBool("all")
+	req := &pb.RequeueDeadLettersRequest{JobId: jobId, All: all}
+	if len(args) == 1 {
+		req.RunId = args[0]
+	}
+	if req.RunId == "" && jobId == "" && !all {
+		log.Fatalln("[-] Pass a run id, --job-id or --all")
+	}
+
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.RequeueDeadLetters(ctx, req)
+	if err != nil{
+		log.Fatalf("[-] Error requeuing dead letters: %v", err)
+	}
+	for _, runId := range resp.RunIds {
+		fmt.Printf("[+] Requeued %s\n", runId)
+	}
+	for _, skipped := range resp.Skipped {
+		fmt.Printf("[-] Skipped %s\n", skipped)
+	}
+	if len(resp.RunIds) == 0 && len(resp.Skipped) == 0 {
+		fmt.Println("[*] Nothing to requeue")
+	}
+}
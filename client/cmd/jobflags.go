@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// jobFlags maps the flags describing a job to the Job fields they set
+var jobFlags = map[string]string{
+	"command": "command",
+	"image": "image",
+	"schedule": "schedule",
+	"timezone": "timezone",
+	"at": "run_at",
+	"in": "run_in",
+	"spread": "spread",
+	"jitter": "jitter_seconds",
+	"not-before": "not_before",
+	"not-after": "not_after",
+	"max-runs": "max_runs",
+	"concurrency": "concurrency_policy",
+	"misfire": "misfire_policy",
+	"misfire-limit": "misfire_limit",
+	"label": "labels",
+	"node-selector": "node_selector",
+	"affinity": "affinity",
+	"anti-affinity": "anti_affinity",
+	"timeout": "timeout",
+	"cpus": "cpus",
+	"memory": "memory_mb",
+	"memory-limit": "limits.memory_mb",
+	"memory-swap": "limits.memory_swap_mb",
+	"cpu-limit": "limits.cpus",
+	"cpu-shares": "limits.cpu_shares",
+	"pids-limit": "limits.pids",
+	"ulimit": "limits.ulimits",
+	"max-attempts": "retry.max_attempts",
+	"backoff": "retry.initial_backoff",
+	"backoff-multiplier": "retry.multiplier",
+	"max-backoff": "retry.max_backoff",
+	"retry-on-exit": "retry.retry_on_exit_codes",
+	"registry-user": "registry_username",
+	"registry-pass": "registry_password",
+	"registry-url": "registry_server",
+}
+
+// addJobFlags registers the flags shared by submit and edit
+func addJobFlags(cmd *cobra.Command){
+	cmd.Flags().StringP("command", "c","test","Command")
+	cmd.Flags().StringP("image", "i","alpine","Docker image")
+	cmd.Flags().StringP("schedule", "s","100","Interval in seconds, cron expression (\"30 2 * * 1-5\") or macro (@hourly, @daily, ...); -1 runs once")
+
+	cmd.Flags().StringP("timezone", "z", "", "IANA time zone for cron schedules, e.g. Europe/Berlin (default: server local time)")
+
+	cmd.Flags().String("at", "", "Run once at this RFC 3339 time, e.g. 2026-01-02T15:04:05Z (implies -s -1)")
+	cmd.Flags().String("in", "", "Run once after this delay, e.g. 45m or 2h30m (implies -s -1)")
+	cmd.Flags().Bool("spread", false, "Shift interval fires by a stable per-job offset so jobs sharing an interval don't fire together")
+	cmd.Flags().Int32("jitter", 0, "Delay each fire by a pseudo-random 0..N seconds")
+	cmd.Flags().String("not-before", "", "RFC 3339 time before which the job does not fire")
+	cmd.Flags().String("not-after", "", "RFC 3339 time after which the job retires")
+	cmd.Flags().Int32("max-runs", 0, "Retire the job after this many runs (0 = unlimited)")
+	cmd.Flags().String("concurrency", "allow", "When a fire comes due while the previous run is in flight: allow, forbid (skip the fire) or replace")
+	cmd.Flags().String("misfire", "fire_once", "What to do with missed fires: skip, fire_once or fire_all")
+	cmd.Flags().Int32("misfire-limit", 10, "Max missed fires replayed with --misfire fire_all")
+
+	cmd.Flags().StringArrayP("label", "l", nil, "Label as key=value, can be repeated")
+	cmd.Flags().StringArray("node-selector", nil, "Only run on workers with this label, as key=value, can be repeated")
+	cmd.Flags().StringArray("affinity", nil, "Prefer workers with this label, as key=value, can be repeated")
+	cmd.Flags().StringArray("anti-affinity", nil, "Avoid workers with this label, as key=value, can be repeated")
+	cmd.Flags().String("timeout", "", "Kill a run still executing after this long, e.g. 10m (default: no limit)")
+	cmd.Flags().Float64("cpus", 0, "CPUs a run needs, e.g. 0.5; it waits for a worker with that many free")
+	cmd.Flags().Int32("memory", 0, "Memory a run needs, in MB; it waits for a worker with that much free")
+	cmd.Flags().Int64("memory-limit", 0, "Memory limit of the container, in MB (default: the server's)")
+	cmd.Flags().Int64("memory-swap", 0, "Memory plus swap limit of the container, in MB; -1 = unlimited swap")
+	cmd.Flags().Float64("cpu-limit", 0, "CPU quota of the container, e.g. 1.5 (default: the server's)")
+	cmd.Flags().Int64("cpu-shares", 0, "Relative CPU weight of the container (Docker's default is 1024)")
+	cmd.Flags().Int64("pids-limit", 0, "Max processes in the container (default: the server's)")
+	cmd.Flags().StringArray("ulimit", nil, "Ulimit as name=soft[:hard], e.g. nofile=1024:2048, can be repeated")
+
+	cmd.Flags().Int32("max-attempts", 1, "Attempts per run, including the first; failed attempts are retried until this many were made")
+	cmd.Flags().String("backoff", "10s", "Delay before the first retry")
+	cmd.Flags().Float64("backoff-multiplier", 2, "Factor the delay grows by with every retry")
+	cmd.Flags().String("max-backoff", "1h", "Longest delay between retries")
+	cmd.Flags().Int32Slice("retry-on-exit", nil, "Only retry attempts that failed with these exit codes (default: any failure)")
+
+	cmd.Flags().String("registry-user", "", "registry username")
+	cmd.Flags().String("registry-pass", "", "registry password")
+	cmd.Flags().String("registry-url", "", "docker.io")
+}
+
+// jobFromFlags builds a Job from the flags and returns it along with the
+// Job fields whose flags were explicitly set
+func jobFromFlags(cmd *cobra.Command) (*pb.Job, []string, error){
+	command, _ := cmd.Flags().GetString("command")
+	image, _ := cmd.Flags().GetString("image")
+	schedule, _ := cmd.Flags().GetString("schedule")
+	timezone, _ := cmd.Flags().GetString("timezone")
+	run_at, _ := cmd.Flags().GetString("at")
+	run_in, _ := cmd.Flags().GetString("in")
+	if run_at != "" || run_in != "" {
+		schedule = "-1" // --at and --in describe one-off jobs
+	}
+	spread, _ := cmd.Flags().GetBool("spread")
+	jitter, _ := cmd.Flags().GetInt32("jitter")
+	not_before, _ := cmd.Flags().GetString("not-before")
+	not_after, _ := cmd.Flags().GetString("not-after")
+	max_runs, _ := cmd.Flags().GetInt32("max-runs")
+	concurrency, _ := cmd.Flags().GetString("concurrency")
+	misfire, _ := cmd.Flags().GetString("misfire")
+	misfire_limit, _ := cmd.Flags().GetInt32("misfire-limit")
+
+	labels, err := labelsFromFlag(cmd, "label")
+	if err != nil {
+		return nil, nil, err
+	}
+	node_selector, err := labelsFromFlag(cmd, "node-selector")
+	if err != nil {
+		return nil, nil, err
+	}
+	affinity, err := labelsFromFlag(cmd, "affinity")
+	if err != nil {
+		return nil, nil, err
+	}
+	anti_affinity, err := labelsFromFlag(cmd, "anti-affinity")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeout, _ := cmd.Flags().GetString("timeout")
+	cpus, _ := cmd.Flags().GetFloat64("cpus")
+	memory, _ := cmd.Flags().GetInt32("memory")
+	memory_limit, _ := cmd.Flags().GetInt64("memory-limit")
+	memory_swap, _ := cmd.Flags().GetInt64("memory-swap")
+	cpu_limit, _ := cmd.Flags().GetFloat64("cpu-limit")
+	cpu_shares, _ := cmd.Flags().GetInt64("cpu-shares")
+	pids_limit, _ := cmd.Flags().GetInt64("pids-limit")
+	ulimit_flags, _ := cmd.Flags().GetStringArray("ulimit")
+	ulimits := []*pb.Ulimit{}
+	for _, flag := range ulimit_flags {
+		ulimit, err := parseUlimit(flag)
+		if err != nil {
+			return nil, nil, err
+		}
+		ulimits = append(ulimits, ulimit)
+	}
+
+	max_attempts, _ := cmd.Flags().GetInt32("max-attempts")
+	backoff, _ := cmd.Flags().GetString("backoff")
+	backoff_multiplier, _ := cmd.Flags().GetFloat64("backoff-multiplier")
+	max_backoff, _ := cmd.Flags().GetString("max-backoff")
+	retry_on_exit, _ := cmd.Flags().GetInt32Slice("retry-on-exit")
+
+	registry_user, _ := cmd.Flags().GetString("registry-user")
+	registry_pass, _ := cmd.Flags().GetString("registry-pass")
+	registry_url, _ := cmd.Flags().GetString("registry-url")
+
+	fields := []string{}
+	for flag, field := range jobFlags {
+		if cmd.Flags().Changed(flag) {
+			fields = append(fields, field)
+		}
+	}
+	if run_at != "" || run_in != "" {
+		fields = append(fields, "schedule")
+	}
+
+	return &pb.Job{Command: command,
+				Schedule: schedule,
+				Image: image,
+				Timezone: timezone,
+				MisfirePolicy: misfire,
+				MisfireLimit: misfire_limit,
+				RunAt: run_at,
+				RunIn: run_in,
+				NotBefore: not_before,
+				NotAfter: not_after,
+				MaxRuns: max_runs,
+				ConcurrencyPolicy: concurrency,
+				Spread: spread,
+				JitterSeconds: jitter,
+				Labels: labels,
+				NodeSelector: node_selector,
+				Affinity: affinity,
+				AntiAffinity: anti_affinity,
+				Timeout: timeout,
+				Cpus: cpus,
+				MemoryMb: memory,
+				Limits: &pb.ResourceLimits{MemoryMb: memory_limit,
+					MemorySwapMb: memory_swap,
+					Cpus: cpu_limit,
+					CpuShares: cpu_shares,
+					Pids: pids_limit,
+					Ulimits: ulimits,},
+				Retry: &pb.RetryPolicy{MaxAttempts: max_attempts,
+					InitialBackoff: backoff,
+					Multiplier: backoff_multiplier,
+					MaxBackoff: max_backoff,
+					RetryOnExitCodes: retry_on_exit,},
+				RegistryUsername: registry_user,
+				RegistryPassword: registry_pass,
+				RegistryServer: registry_url,}, fields, nil
+}
+
+// labelsFromFlag parses the key=value pairs given to a repeatable flag
+func labelsFromFlag(cmd *cobra.Command, name string) (map[string]string, error) {
+	flags, _ := cmd.Flags().GetStringArray(name)
+	labels := map[string]string{}
+	for _, label := range flags {
+		k, v, ok := strings.Cut(label, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid %s %q, want key=value", name, label)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// parseUlimit parses a --ulimit flag, name=soft[:hard]; without a hard limit
+// it is the same as the soft one
+func parseUlimit(flag string) (*pb.Ulimit, error) {
+	name, values, ok := strings.Cut(flag, "=")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid ulimit %q, want name=soft[:hard]", flag)
+	}
+	soft, hard, hasHard := strings.Cut(values, ":")
+	ulimit := &pb.Ulimit{Name: name}
+	var err error
+	if ulimit.Soft, err = strconv.ParseInt(soft, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid ulimit %q: %v", flag, err)
+	}
+	ulimit.Hard = ulimit.Soft
+	if hasHard {
+		if ulimit.Hard, err = strconv.ParseInt(hard, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid ulimit %q: %v", flag, err)
+		}
+	}
+	return ulimit, nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var list = &cobra.Command{
+	Use:   "list [--status <status>] [--image <image>] [--label key=value]",
+	Short: "List jobs",
+	Long: `List the jobs known to the server, optionally filtered by status, image and labels`,
+	Run : listJobs,
+}
+
+func init(){
+	rootCmd.AddCommand(list)
+
+	list.Flags().String("status", "", "Only jobs with this status (QUEUED, RUNNING, COMPLETED, FAILED, RETIRED)")
+	list.Flags().StringP("image", "i", "", "Only jobs using this image")
+	list.Flags().StringArrayP("label", "l", nil, "Only jobs with this label (key=value), can be repeated")
+	list.Flags().String("sort", "id", "Order by id, next_run, created, status or image")
+	list.Flags().Bool("desc", false, "Sort in descending order")
+	list.Flags().Int32P("page-size", "n", 50, "Jobs per page")
+	list.Flags().String("page-token", "", "Token printed at the end of the previous page")
+}
+
+func listJobs(cmd *cobra.Command, args []string) {
+	status, _ := cmd.Flags().GetString("status")
+	image, _ := cmd.Flags().GetString("image")
+	labels, _ := cmd.Flags().GetStringArray("label")
+	order_by, _ := cmd.Flags().GetString("sort")
+	desc, _ := cmd.Flags().GetBool("desc")
+	page_size, _ := cmd.Flags().GetInt32("page-size")
+	page_token, _ := cmd.Flags().GetString("page-token")
+
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.ListJobs(ctx, &pb.ListJobsRequest{
+		PageSize: page_size,
+		PageToken: page_token,
+		Status: status,
+		Image: image,
+		Labels: labels,
+		OrderBy: order_by,
+		Descending: desc,
+	})
+	if err != nil{
+		log.Fatalf("[-] Error listing jobs: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB ID\tSTATUS\tIMAGE\tSCHEDULE\tNEXT RUN\tRUNS\tLABELS")
+	for _, job := range resp.Jobs {
+		status := job.Status
+		if job.Paused {
+			status += " (paused)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", job.JobId, status, job.Image, job.Schedule,
+			formatUnix(job.NextRun), job.Runs, formatLabels(job.Labels))
+	}
+	w.Flush()
+	if resp.NextPageToken != "" {
+		fmt.Printf("\nMore jobs: --page-token %s\n", resp.NextPageToken)
+	}
+}
+
+// formatLabels renders labels as sorted k=v pairs
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
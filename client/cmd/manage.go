@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete -j <job id>",
+	Short: "Delete a job along with its run history",
+	Long: `Delete a job along with its run history. Runs already dispatched finish on their workers but are not recorded`,
+	Run : deleteJob,
+}
+
+var edit = &cobra.Command{
+	Use:   "edit -j <job id> [flags to change]",
+	Short: "Change a submitted job",
+	Long: `Change a submitted job. Only the flags given are updated, e.g. "edit -j <id> -i alpine:3.20 -s @hourly".
+Changing the schedule, time zone or bounds recomputes the next fire. --label replaces all labels`,
+	Run : editJob,
+}
+
+var pause = &cobra.Command{
+	Use:   "pause -j <job id>",
+	Short: "Stop a job from firing until it is resumed",
+	Long: `Stop a job from firing until it is resumed. Runs already dispatched are left to finish`,
+	Run : pauseJob,
+}
+
+var resume = &cobra.Command{
+	Use:   "resume -j <job id>",
+	Short: "Let a paused job fire again",
+	Long: `Let a paused job fire again. Fires that came due while it was paused are skipped`,
+	Run : resumeJob,
+}
+
+func init(){
+	for _, cmd := range []*cobra.Command{deleteCmd, edit, pause, resume} {
+		rootCmd.AddCommand(cmd)
+		cmd.Flags().StringP("client-id", "j", "", "Job id")
+		cmd.MarkFlagRequired("client-id")
+	}
+	addJobFlags(edit)
+}
+
+func deleteJob(cmd *cobra.Command, args []string) {
+	id, _ := cmd.Flags().GetString("client-id")
+	manageJob(func(ctx context.Context, client pb.SchedulerClient) (*pb.JobResponse, error) {
+		return client.DeleteJob(ctx, &pb.DeleteJobRequest{JobId: id})
+	})
+}
+
+func editJob(cmd *cobra.Command, args []string) {
+	id, _ := cmd.Flags().GetString("client-id")
+	job, fields, err := jobFromFlags(cmd)
+	if err != nil {
+		log.Fatalf("[-] %v", err)
+	}
+	if len(fields) == 0 {
+		log.Fatalln("[-] Nothing to change, pass the flags to update (see edit --help)")
+	}
+	job.Id = id
+	manageJob(func(ctx context.Context, client pb.SchedulerClient) (*pb.JobResponse, error) {
+		return client.UpdateJob(ctx, &pb.UpdateJobRequest{Job: job, UpdateMask: fields})
+	})
+}
+
+func pauseJob(cmd *cobra.Command, args []string) {
+	id, _ := cmd.Flags().GetString("client-id")
+	manageJob(func(ctx context.Context, client pb.SchedulerClient) (*pb.JobResponse, error) {
+		return client.PauseJob(ctx, &pb.PauseJobRequest{JobId: id})
+	})
+}
+
+func resumeJob(cmd *cobra.Command, args []string) {
+	id, _ := cmd.Flags().GetString("client-id")
+	manageJob(func(ctx context.Context, client pb.SchedulerClient) (*pb.JobResponse, error) {
+		return client.ResumeJob(ctx, &pb.ResumeJobRequest{JobId: id})
+	})
+}
+
+// manageJob connects to the server, makes one call and prints its response
+func manageJob(call func(ctx context.Context, client pb.SchedulerClient) (*pb.JobResponse, error)) {
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := call(ctx, client)
+	if err != nil{
+		log.Fatalf("[-] %v", err)
+	}
+	log.Println(response.GetMessage(), response.GetId())
+}
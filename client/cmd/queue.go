@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show the dispatch queue",
+	Long: `Show how many runs are waiting for a worker and how long the oldest has been waiting`,
+	Run : queueStats,
+}
+
+func init(){
+	rootCmd.AddCommand(queueCmd)
+}
+
+func queueStats(cmd *cobra.Command, args []string) {
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := client.GetQueueStats(ctx, &pb.Empty{})
+	if err != nil{
+		log.Fatalf("[-] Error getting queue stats: %v", err)
+	}
+	fmt.Printf("Depth:       %d\n", stats.Depth)
+	fmt.Printf("Delivering:  %d\n", stats.Delivering)
+	fmt.Printf("Delayed:     %d\n", stats.Delayed)
+	if stats.OldestEnqueuedAt != 0 {
+		fmt.Printf("Oldest:      %s (%s ago)\n", formatUnix(stats.OldestEnqueuedAt), time.Duration(stats.OldestAgeSeconds)*time.Second)
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var runs = &cobra.Command{
+	Use:   "runs -j <job id> | runs --run-id <run id>",
+	Short: "List the runs of a job, or show one run",
+	Long: `List the most recent runs of a job, or show a single run including its output`,
+	Run : listRuns,
+}
+
+func init(){
+	rootCmd.AddCommand(runs)
+
+	runs.Flags().StringP("job-id", "j", "", "Job id")
+	runs.Flags().String("run-id", "", "Run id, shows that run and its output")
+	runs.Flags().Int32P("limit", "n", 20, "Max runs to list")
+}
+
+func listRuns(cmd *cobra.Command, args []string) {
+	jobId, _ := cmd.Flags().GetString("job-id")
+	runId, _ := cmd.Flags().GetString("run-id")
+	limit, _ := cmd.Flags().GetInt32("limit")
+	if jobId == "" && runId == "" {
+		log.Fatalln("[-] Either --job-id or --run-id is required")
+	}
+
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if runId != "" {
+		run, err := client.GetRun(ctx, &pb.GetRunRequest{RunId: runId})
+		if err != nil{
+			log.Fatalf("[-] Error getting run: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Run:\t%s\nJob:\t%s\nStatus:\t%s\nWorker:\t%s\n", run.RunId, run.JobId, run.Status, run.WorkerId)
+		fmt.Fprintf(w, "Scheduled:\t%s\nStarted:\t%s\nFinished:\t%s\n", formatUnix(run.ScheduledAt), formatUnix(run.StartedAt), formatUnix(run.FinishedAt))
+		if run.ContainerStartedAt != 0 {
+			fmt.Fprintf(w, "Container:\t%s to %s\n", formatUnix(run.ContainerStartedAt), formatUnix(run.ContainerFinishedAt))
+		}
+		fmt.Fprintf(w, "Duration:\t%s\nExit code:\t%s\nReason:\t%s\nAttempt:\t%d\n", runDuration(run), formatExitCode(run.ExitCode), orDash(run.Reason), run.Attempt)
+		w.Flush()
+		if len(run.Attempts) > 0 {
+			fmt.Println("Earlier attempts:")
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "  ATTEMPT\tWORKER\tSTARTED\tFINISHED\tEXIT\tREASON")
+			for _, a := range run.Attempts {
+				fmt.Fprintf(w, "  %d\t%s\t%s\t%s\t%s\t%s\n", a.Attempt, orDash(a.WorkerId),
+					formatUnix(a.StartedAt), formatUnix(a.FinishedAt), formatExitCode(a.ExitCode), orDash(a.Reason))
+			}
+			w.Flush()
+		}
+		fmt.Printf("Output:\n%s\n", run.Output)
+		return
+	}
+
+	resp, err := client.ListRuns(ctx, &pb.ListRunsRequest{JobId: jobId, Limit: limit})
+	if err != nil{
+		log.Fatalf("[-] Error listing runs: %v", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN ID\tSTATUS\tATTEMPT\tWORKER\tSCHEDULED\tSTARTED\tDURATION\tEXIT")
+	for _, run := range resp.Runs {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n", run.RunId, run.Status, run.Attempt, orDash(run.WorkerId),
+			formatUnix(run.ScheduledAt), formatUnix(run.StartedAt),
+			runDuration(run), formatExitCode(run.ExitCode))
+	}
+	w.Flush()
+}
+
+// formatUnix renders unix seconds in local time, "-" for unset
+func formatUnix(sec int64) string {
+	if sec == 0 {
+		return "-"
+	}
+	return time.Unix(sec, 0).Format("2006-01-02 15:04:05")
+}
+
+func formatDuration(start, end int64) string {
+	if start == 0 || end == 0 {
+		return "-"
+	}
+	return (time.Duration(end-start) * time.Second).String()
+}
+
+// runDuration is how long the container of a run ran, or if the worker did
+// not report that, the time from dispatch to result
+func runDuration(run *pb.Run) string {
+	if run.ContainerStartedAt != 0 {
+		return formatDuration(run.ContainerStartedAt, run.ContainerFinishedAt)
+	}
+	return formatDuration(run.StartedAt, run.FinishedAt)
+}
+
+func formatExitCode(code int32) string {
+	if code < 0 {
+		return "-"
+	}
+	return fmt.Sprint(code)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+var workersCmd = &cobra.Command{
+	Use:   "workers [--status <status>] | workers -w <worker id>",
+	Short: "List workers, or show one",
+	Long: `List the workers that connected to the server recently, with their capacity and the runs they hold, or show one of them`,
+	Run : listWorkers,
+}
+
+func init(){
+	rootCmd.AddCommand(workersCmd)
+
+	workersCmd.Flags().StringP("worker-id", "w", "", "Worker id, shows that worker and the runs it holds")
+	workersCmd.Flags().String("status", "", "Only workers with this status: connected, unresponsive or disconnected")
+}
+
+func listWorkers(cmd *cobra.Command, args []string) {
+	workerId, _ := cmd.Flags().GetString("worker-id")
+	status, _ := cmd.Flags().GetString("status")
+
+	conn, client := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if workerId != "" {
+		worker, err := client.GetWorker(ctx, &pb.GetWorkerRequest{WorkerId: workerId})
+		if err != nil{
+			log.Fatalf("[-] Error getting worker: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Worker:\t%s\nStatus:\t%s\nVersion:\t%s\nLabels:\t%s\n", worker.WorkerId, workerState(worker), orDash(worker.Version), formatLabels(worker.Labels))
+		fmt.Fprintf(w, "Slots:\t%d/%d\nCPUs:\t%s\nMemory:\t%s\n", len(worker.RunIds), worker.Slots,
+			formatCapacity(fmt.Sprintf("%g", worker.CpusUsed), fmt.Sprintf("%g", worker.Cpus), worker.Cpus == 0),
+			formatCapacity(fmt.Sprint(worker.MemoryUsedMb), fmt.Sprintf("%d MB", worker.MemoryMb), worker.MemoryMb == 0))
+		fmt.Fprintf(w, "Connected:\t%s\nLast heartbeat:\t%s\nDisconnected:\t%s\n", formatUnix(worker.ConnectedAt), formatUnix(worker.LastHeartbeat), formatUnix(worker.DisconnectedAt))
+		w.Flush()
+		fmt.Println("Runs:")
+		for _, runId := range worker.RunIds {
+			fmt.Printf("  %s\n", runId)
+		}
+		return
+	}
+
+	resp, err := client.ListWorkers(ctx, &pb.ListWorkersRequest{Status: status})
+	if err != nil{
+		log.Fatalf("[-] Error listing workers: %v", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKER ID\tSTATUS\tSLOTS\tCPUS\tMEMORY\tLAST HEARTBEAT\tVERSION\tLABELS")
+	for _, worker := range resp.Workers {
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\t%s\t%s\t%s\n", worker.WorkerId, workerState(worker), len(worker.RunIds), worker.Slots,
+			formatCapacity(fmt.Sprintf("%g", worker.CpusUsed), fmt.Sprintf("%g", worker.Cpus), worker.Cpus == 0),
+			formatCapacity(fmt.Sprint(worker.MemoryUsedMb), fmt.Sprintf("%d MB", worker.MemoryMb), worker.MemoryMb == 0),
+			formatUnix(worker.LastHeartbeat), orDash(worker.Version), formatLabels(worker.Labels))
+	}
+	w.Flush()
+}
+
+// formatCapacity renders how much of a worker's capacity is requested, as
+// "used/total", with "?" as the total when the capacity is unknown
+func formatCapacity(used, total string, unknown bool) string {
+	if unknown {
+		return used + "/?"
+	}
+	return used + "/" + total
+}
+
+// workerState is the status of a worker, followed by DRAINING or CORDONED if
+// it gets no new runs
+func workerState(worker *pb.Worker) string {
+	switch {
+	case worker.Draining:
+		return worker.Status + ",DRAINING"
+	case worker.Cordoned:
+		return worker.Status + ",CORDONED"
+	}
+	return worker.Status
+}
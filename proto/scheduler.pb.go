@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
 // 	protoc-gen-go v1.36.11
-// 	protoc        v3.21.12
+// 	protoc        (unknown)
 // source: proto/scheduler.proto
 
 package proto
@@ -22,33 +22,2798 @@ const (
 )
 
 type Job struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Command           string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Schedule          string                 `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Image             string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	RegistryUsername  string                 `protobuf:"bytes,5,opt,name=registry_username,json=registryUsername,proto3" json:"registry_username,omitempty"`
+	RegistryPassword  string                 `protobuf:"bytes,6,opt,name=registry_password,json=registryPassword,proto3" json:"registry_password,omitempty"`
+	RegistryServer    string                 `protobuf:"bytes,7,opt,name=registry_server,json=registryServer,proto3" json:"registry_server,omitempty"`
+	Timezone          string                 `protobuf:"bytes,8,opt,name=timezone,proto3" json:"timezone,omitempty"`                                                                                                        // IANA zone cron schedules are evaluated in, e.g. "Asia/Kolkata". Empty = server local time
+	MisfirePolicy     string                 `protobuf:"bytes,9,opt,name=misfire_policy,json=misfirePolicy,proto3" json:"misfire_policy,omitempty"`                                                                         // What to do with fires missed while the server was down or the queue was full: "skip", "fire_once" (default), "fire_all"
+	MisfireLimit      int32                  `protobuf:"varint,10,opt,name=misfire_limit,json=misfireLimit,proto3" json:"misfire_limit,omitempty"`                                                                          // Max missed fires replayed by "fire_all" (default 10)
+	RunAt             string                 `protobuf:"bytes,11,opt,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`                                                                                                // One-off jobs only: RFC 3339 time to run at instead of as soon as possible
+	RunIn             string                 `protobuf:"bytes,12,opt,name=run_in,json=runIn,proto3" json:"run_in,omitempty"`                                                                                                // One-off jobs only: delay before running, e.g. "45m". Turned into run_at on submit
+	NotBefore         string                 `protobuf:"bytes,13,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`                                                                                    // RFC 3339, no fires before this time
+	NotAfter          string                 `protobuf:"bytes,14,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`                                                                                       // RFC 3339, the job retires once its next fire would be later than this
+	MaxRuns           int32                  `protobuf:"varint,15,opt,name=max_runs,json=maxRuns,proto3" json:"max_runs,omitempty"`                                                                                         // The job retires after this many runs, 0 = unlimited
+	ConcurrencyPolicy string                 `protobuf:"bytes,16,opt,name=concurrency_policy,json=concurrencyPolicy,proto3" json:"concurrency_policy,omitempty"`                                                            // When a fire comes due while a previous run is in flight: "allow" (default), "forbid" (skip the fire), "replace"
+	RunId             string                 `protobuf:"bytes,17,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`                                                                                                // Set by the server on every dispatch, identifies the run
+	Spread            bool                   `protobuf:"varint,18,opt,name=spread,proto3" json:"spread,omitempty"`                                                                                                          // Interval schedules only: shift fires by a stable, per-job offset within the interval
+	JitterSeconds     int32                  `protobuf:"varint,19,opt,name=jitter_seconds,json=jitterSeconds,proto3" json:"jitter_seconds,omitempty"`                                                                       // Delay each fire by a pseudo-random 0..jitter_seconds
+	Labels            map[string]string      `protobuf:"bytes,20,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                                 // Free-form key/value labels, used to filter ListJobs
+	Retry             *RetryPolicy           `protobuf:"bytes,21,opt,name=retry,proto3" json:"retry,omitempty"`                                                                                                             // How failed runs are retried, no retries if unset
+	Timeout           string                 `protobuf:"bytes,22,opt,name=timeout,proto3" json:"timeout,omitempty"`                                                                                                         // Max execution time of a run, e.g. "10m"; the worker kills the container after it. Empty = no limit
+	Cpus              float64                `protobuf:"fixed64,23,opt,name=cpus,proto3" json:"cpus,omitempty"`                                                                                                             // CPUs a run needs, e.g. 0.5; it only goes to a worker with that much free. 0 = no request
+	MemoryMb          int32                  `protobuf:"varint,24,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`                                                                                      // Memory a run needs, in MB. 0 = no request
+	Limits            *ResourceLimits        `protobuf:"bytes,25,opt,name=limits,proto3" json:"limits,omitempty"`                                                                                                           // Limits the worker puts on the container; unset ones take the server's defaults
+	NodeSelector      map[string]string      `protobuf:"bytes,26,rep,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Labels a worker must have, all of them, to be handed runs of the job
+	Affinity          map[string]string      `protobuf:"bytes,27,rep,name=affinity,proto3" json:"affinity,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                             // Worker labels to prefer: runs go to the free worker matching most of them
+	AntiAffinity      map[string]string      `protobuf:"bytes,28,rep,name=anti_affinity,json=antiAffinity,proto3" json:"anti_affinity,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Worker labels to avoid: runs go to the free worker matching fewest of them
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_proto_scheduler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Job) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *Job) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Job) GetRegistryUsername() string {
+	if x != nil {
+		return x.RegistryUsername
+	}
+	return ""
+}
+
+func (x *Job) GetRegistryPassword() string {
+	if x != nil {
+		return x.RegistryPassword
+	}
+	return ""
+}
+
+func (x *Job) GetRegistryServer() string {
+	if x != nil {
+		return x.RegistryServer
+	}
+	return ""
+}
+
+func (x *Job) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Job) GetMisfirePolicy() string {
+	if x != nil {
+		return x.MisfirePolicy
+	}
+	return ""
+}
+
+func (x *Job) GetMisfireLimit() int32 {
+	if x != nil {
+		return x.MisfireLimit
+	}
+	return 0
+}
+
+func (x *Job) GetRunAt() string {
+	if x != nil {
+		return x.RunAt
+	}
+	return ""
+}
+
+func (x *Job) GetRunIn() string {
+	if x != nil {
+		return x.RunIn
+	}
+	return ""
+}
+
+func (x *Job) GetNotBefore() string {
+	if x != nil {
+		return x.NotBefore
+	}
+	return ""
+}
+
+func (x *Job) GetNotAfter() string {
+	if x != nil {
+		return x.NotAfter
+	}
+	return ""
+}
+
+func (x *Job) GetMaxRuns() int32 {
+	if x != nil {
+		return x.MaxRuns
+	}
+	return 0
+}
+
+func (x *Job) GetConcurrencyPolicy() string {
+	if x != nil {
+		return x.ConcurrencyPolicy
+	}
+	return ""
+}
+
+func (x *Job) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Job) GetSpread() bool {
+	if x != nil {
+		return x.Spread
+	}
+	return false
+}
+
+func (x *Job) GetJitterSeconds() int32 {
+	if x != nil {
+		return x.JitterSeconds
+	}
+	return 0
+}
+
+func (x *Job) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Job) GetRetry() *RetryPolicy {
+	if x != nil {
+		return x.Retry
+	}
+	return nil
+}
+
+func (x *Job) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *Job) GetCpus() float64 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *Job) GetMemoryMb() int32 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *Job) GetLimits() *ResourceLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *Job) GetNodeSelector() map[string]string {
+	if x != nil {
+		return x.NodeSelector
+	}
+	return nil
+}
+
+func (x *Job) GetAffinity() map[string]string {
+	if x != nil {
+		return x.Affinity
+	}
+	return nil
+}
+
+func (x *Job) GetAntiAffinity() map[string]string {
+	if x != nil {
+		return x.AntiAffinity
+	}
+	return nil
+}
+
+// Limits applied to a run's container through its Docker HostConfig. 0 = no
+// limit unless the server sets a default
+type ResourceLimits struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MemoryMb      int64                  `protobuf:"varint,1,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`               // Hard memory limit, in MB. Must cover the job's memory request
+	MemorySwapMb  int64                  `protobuf:"varint,2,opt,name=memory_swap_mb,json=memorySwapMb,proto3" json:"memory_swap_mb,omitempty"` // Memory plus swap, in MB; at least memory_mb, -1 = unlimited swap. 0 = Docker's default, twice memory_mb
+	Cpus          float64                `protobuf:"fixed64,3,opt,name=cpus,proto3" json:"cpus,omitempty"`                                      // CPU quota, e.g. 1.5. Must cover the job's cpus request
+	CpuShares     int64                  `protobuf:"varint,4,opt,name=cpu_shares,json=cpuShares,proto3" json:"cpu_shares,omitempty"`            // Relative CPU weight against other containers (Docker's default is 1024)
+	Pids          int64                  `protobuf:"varint,5,opt,name=pids,proto3" json:"pids,omitempty"`                                       // Max processes in the container
+	Ulimits       []*Ulimit              `protobuf:"bytes,6,rep,name=ulimits,proto3" json:"ulimits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourceLimits) Reset() {
+	*x = ResourceLimits{}
+	mi := &file_proto_scheduler_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceLimits) ProtoMessage() {}
+
+func (x *ResourceLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceLimits.ProtoReflect.Descriptor instead.
+func (*ResourceLimits) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResourceLimits) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetMemorySwapMb() int64 {
+	if x != nil {
+		return x.MemorySwapMb
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetCpus() float64 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetCpuShares() int64 {
+	if x != nil {
+		return x.CpuShares
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetPids() int64 {
+	if x != nil {
+		return x.Pids
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetUlimits() []*Ulimit {
+	if x != nil {
+		return x.Ulimits
+	}
+	return nil
+}
+
+type Ulimit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // e.g. "nofile", "nproc"
+	Soft          int64                  `protobuf:"varint,2,opt,name=soft,proto3" json:"soft,omitempty"`
+	Hard          int64                  `protobuf:"varint,3,opt,name=hard,proto3" json:"hard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ulimit) Reset() {
+	*x = Ulimit{}
+	mi := &file_proto_scheduler_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ulimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ulimit) ProtoMessage() {}
+
+func (x *Ulimit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ulimit.ProtoReflect.Descriptor instead.
+func (*Ulimit) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Ulimit) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Ulimit) GetSoft() int64 {
+	if x != nil {
+		return x.Soft
+	}
+	return 0
+}
+
+func (x *Ulimit) GetHard() int64 {
+	if x != nil {
+		return x.Hard
+	}
+	return 0
+}
+
+// A failed run is retried, as a new attempt of the same run, after a backoff
+// of initial_backoff * multiplier^(failed attempts - 1), capped at max_backoff
+type RetryPolicy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MaxAttempts      int32                  `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`                           // Attempts per run, including the first. 0 or 1 = no retries
+	InitialBackoff   string                 `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`                   // e.g. "30s" (default 10s)
+	Multiplier       float64                `protobuf:"fixed64,3,opt,name=multiplier,proto3" json:"multiplier,omitempty"`                                               // Default 2
+	MaxBackoff       string                 `protobuf:"bytes,4,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`                               // Default 1h
+	RetryOnExitCodes []int32                `protobuf:"varint,5,rep,packed,name=retry_on_exit_codes,json=retryOnExitCodes,proto3" json:"retry_on_exit_codes,omitempty"` // Only retry these exit codes, any failure if empty. -1 = the container could not be run or timed out
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_proto_scheduler_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialBackoff() string {
+	if x != nil {
+		return x.InitialBackoff
+	}
+	return ""
+}
+
+func (x *RetryPolicy) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxBackoff() string {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return ""
+}
+
+func (x *RetryPolicy) GetRetryOnExitCodes() []int32 {
+	if x != nil {
+		return x.RetryOnExitCodes
+	}
+	return nil
+}
+
+// Worker sends this to the server
+type JobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobResponse) Reset() {
+	*x = JobResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobResponse) ProtoMessage() {}
+
+func (x *JobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobResponse.ProtoReflect.Descriptor instead.
+func (*JobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *JobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *JobResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type JobStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                                    // "QUEUED" (waiting for a worker), "RUNNING", "RETRYING" (backing off), "COMPLETED", "FAILED", "TIMED_OUT", "CANCELLED", "RETIRED"
+	Output        string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`                                    // The logs (e.g., "Hello from Docker")
+	Misfires      []*Misfire             `protobuf:"bytes,4,rep,name=misfires,proto3" json:"misfires,omitempty"`                                // Most recent skipped fires, oldest first
+	MisfireCount  int32                  `protobuf:"varint,5,opt,name=misfire_count,json=misfireCount,proto3" json:"misfire_count,omitempty"`   // Total number of skipped fires
+	NextRun       int64                  `protobuf:"varint,6,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`                  // Unix seconds of the next fire, 0 if none
+	Runs          int32                  `protobuf:"varint,7,opt,name=runs,proto3" json:"runs,omitempty"`                                       // Number of times the job has been dispatched
+	RetiredReason string                 `protobuf:"bytes,8,opt,name=retired_reason,json=retiredReason,proto3" json:"retired_reason,omitempty"` // Set once the job is RETIRED
+	Paused        bool                   `protobuf:"varint,9,opt,name=paused,proto3" json:"paused,omitempty"`                                   // Paused jobs do not fire until resumed
+	RunId         string                 `protobuf:"bytes,10,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`                        // Latest run dispatched to a worker
+	WorkerId      string                 `protobuf:"bytes,11,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`               // Worker executing, or that executed, the latest run
+	StartedAt     int64                  `protobuf:"varint,12,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`           // Unix seconds the latest run was dispatched, 0 if none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatusResponse) Reset() {
+	*x = JobStatusResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusResponse) ProtoMessage() {}
+
+func (x *JobStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusResponse.ProtoReflect.Descriptor instead.
+func (*JobStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *JobStatusResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetMisfires() []*Misfire {
+	if x != nil {
+		return x.Misfires
+	}
+	return nil
+}
+
+func (x *JobStatusResponse) GetMisfireCount() int32 {
+	if x != nil {
+		return x.MisfireCount
+	}
+	return 0
+}
+
+func (x *JobStatusResponse) GetNextRun() int64 {
+	if x != nil {
+		return x.NextRun
+	}
+	return 0
+}
+
+func (x *JobStatusResponse) GetRuns() int32 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *JobStatusResponse) GetRetiredReason() string {
+	if x != nil {
+		return x.RetiredReason
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *JobStatusResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+// A scheduled fire that was never dispatched
+type Misfire struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledAt   int64                  `protobuf:"varint,1,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"` // Unix seconds
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Misfire) Reset() {
+	*x = Misfire{}
+	mi := &file_proto_scheduler_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Misfire) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Misfire) ProtoMessage() {}
+
+func (x *Misfire) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Misfire.ProtoReflect.Descriptor instead.
+func (*Misfire) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Misfire) GetScheduledAt() int64 {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return 0
+}
+
+func (x *Misfire) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type JobStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatusRequest) Reset() {
+	*x = JobStatusRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusRequest) ProtoMessage() {}
+
+func (x *JobStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusRequest.ProtoReflect.Descriptor instead.
+func (*JobStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *JobStatusRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type WorkerHello struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`                                                       // e.g., "worker-1"
+	MemoryMb      int32                  `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`                                                      // Memory of the worker's host, e.g. 2048. 0 = unknown, memory requests are not enforced
+	Slots         int32                  `protobuf:"varint,3,opt,name=slots,proto3" json:"slots,omitempty"`                                                                            // Runs the worker executes at once; the server hands it no more than that. 0 = 1
+	Cpus          float64                `protobuf:"fixed64,4,opt,name=cpus,proto3" json:"cpus,omitempty"`                                                                             // CPUs of the worker's host. 0 = unknown, CPU requests are not enforced
+	Labels        map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // e.g. "disk": "large", matched against job node selectors and affinities
+	Version       string                 `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`                                                                         // Build of the worker binary
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerHello) Reset() {
+	*x = WorkerHello{}
+	mi := &file_proto_scheduler_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerHello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerHello) ProtoMessage() {}
+
+func (x *WorkerHello) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerHello.ProtoReflect.Descriptor instead.
+func (*WorkerHello) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WorkerHello) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *WorkerHello) GetMemoryMb() int32 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *WorkerHello) GetSlots() int32 {
+	if x != nil {
+		return x.Slots
+	}
+	return 0
+}
+
+func (x *WorkerHello) GetCpus() float64 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *WorkerHello) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *WorkerHello) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// Sent by Worker ONLY when finished
+type JobResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"` // True = Exit Code 0, False = Crashed. The server goes by exit_code; this only matters for workers that do not report one
+	Output        string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`
+	RunId         string                 `protobuf:"bytes,4,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`              // run_id of the dispatched Job
+	ExitCode      int32                  `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`    // Exit code of the container, -1 if it could not be run
+	TimedOut      bool                   `protobuf:"varint,6,opt,name=timed_out,json=timedOut,proto3" json:"timed_out,omitempty"`    // The worker killed the container once the job's timeout elapsed
+	OomKilled     bool                   `protobuf:"varint,7,opt,name=oom_killed,json=oomKilled,proto3" json:"oom_killed,omitempty"` // The container was killed for running out of memory
+	Reason        string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`                         // Why the container ended, e.g. "OOMKilled", "killed by signal 9 (SIGKILL)", or the error that kept it from running
+	StartedAt     int64                  `protobuf:"varint,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // Unix seconds the container started, from ContainerInspect; 0 if it never did
+	FinishedAt    int64                  `protobuf:"varint,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,11,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"` // Worker that ran it. Results from a worker that no longer holds the run are ignored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobResult) Reset() {
+	*x = JobResult{}
+	mi := &file_proto_scheduler_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobResult) ProtoMessage() {}
+
+func (x *JobResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobResult.ProtoReflect.Descriptor instead.
+func (*JobResult) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *JobResult) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *JobResult) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *JobResult) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *JobResult) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *JobResult) GetTimedOut() bool {
+	if x != nil {
+		return x.TimedOut
+	}
+	return false
+}
+
+func (x *JobResult) GetOomKilled() bool {
+	if x != nil {
+		return x.OomKilled
+	}
+	return false
+}
+
+func (x *JobResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *JobResult) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *JobResult) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *JobResult) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_proto_scheduler_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{10}
+}
+
+// One execution of a job
+type Run struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	RunId               string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	JobId               string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId            string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Status              string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                               // "QUEUED", "RUNNING", "RETRYING", "COMPLETED", "FAILED", "TIMED_OUT", "REPLACED", "CANCELLED"
+	ScheduledAt         int64                  `protobuf:"varint,5,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"` // Unix seconds, 0 if not yet
+	StartedAt           int64                  `protobuf:"varint,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt          int64                  `protobuf:"varint,7,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	ExitCode            int32                  `protobuf:"varint,8,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"` // -1 if unknown
+	Output              string                 `protobuf:"bytes,9,opt,name=output,proto3" json:"output,omitempty"`                      // Only set by GetRun; output of the latest attempt
+	Attempt             int32                  `protobuf:"varint,10,opt,name=attempt,proto3" json:"attempt,omitempty"`                  // Current attempt, from 1
+	Attempts            []*RunAttempt          `protobuf:"bytes,11,rep,name=attempts,proto3" json:"attempts,omitempty"`                 // Earlier attempts that failed and were retried
+	OomKilled           bool                   `protobuf:"varint,12,opt,name=oom_killed,json=oomKilled,proto3" json:"oom_killed,omitempty"`
+	Reason              string                 `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`                                                      // Why the container ended, see JobResult.reason
+	ContainerStartedAt  int64                  `protobuf:"varint,14,opt,name=container_started_at,json=containerStartedAt,proto3" json:"container_started_at,omitempty"` // Unix seconds, as reported by the worker
+	ContainerFinishedAt int64                  `protobuf:"varint,15,opt,name=container_finished_at,json=containerFinishedAt,proto3" json:"container_finished_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Run) Reset() {
+	*x = Run{}
+	mi := &file_proto_scheduler_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Run) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Run) ProtoMessage() {}
+
+func (x *Run) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Run.ProtoReflect.Descriptor instead.
+func (*Run) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Run) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Run) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *Run) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *Run) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Run) GetScheduledAt() int64 {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return 0
+}
+
+func (x *Run) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *Run) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *Run) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *Run) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *Run) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Run) GetAttempts() []*RunAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *Run) GetOomKilled() bool {
+	if x != nil {
+		return x.OomKilled
+	}
+	return false
+}
+
+func (x *Run) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Run) GetContainerStartedAt() int64 {
+	if x != nil {
+		return x.ContainerStartedAt
+	}
+	return 0
+}
+
+func (x *Run) GetContainerFinishedAt() int64 {
+	if x != nil {
+		return x.ContainerFinishedAt
+	}
+	return 0
+}
+
+type RunAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attempt       int32                  `protobuf:"varint,1,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // Unix seconds
+	FinishedAt    int64                  `protobuf:"varint,4,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	ExitCode      int32                  `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	OomKilled     bool                   `protobuf:"varint,6,opt,name=oom_killed,json=oomKilled,proto3" json:"oom_killed,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunAttempt) Reset() {
+	*x = RunAttempt{}
+	mi := &file_proto_scheduler_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunAttempt) ProtoMessage() {}
+
+func (x *RunAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunAttempt.ProtoReflect.Descriptor instead.
+func (*RunAttempt) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RunAttempt) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *RunAttempt) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *RunAttempt) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *RunAttempt) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *RunAttempt) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *RunAttempt) GetOomKilled() bool {
+	if x != nil {
+		return x.OomKilled
+	}
+	return false
+}
+
+func (x *RunAttempt) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ListRunsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // Max runs returned, most recent first (default 20)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsRequest) Reset() {
+	*x = ListRunsRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsRequest) ProtoMessage() {}
+
+func (x *ListRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListRunsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListRunsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ListRunsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListRunsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runs          []*Run                 `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsResponse) Reset() {
+	*x = ListRunsResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsResponse) ProtoMessage() {}
+
+func (x *ListRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListRunsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListRunsResponse) GetRuns() []*Run {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+type GetRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunRequest) Reset() {
+	*x = GetRunRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunRequest) ProtoMessage() {}
+
+func (x *GetRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunRequest.ProtoReflect.Descriptor instead.
+func (*GetRunRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetRunRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Default 50, max 500
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                        // Only jobs with this status
+	Image         string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`                          // Only jobs using this image ("alpine" also matches "alpine:3.20")
+	Labels        []string               `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty"`                        // "key=value" selectors, all must match
+	OrderBy       string                 `protobuf:"bytes,6,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // "id" (default), "next_run", "created", "status" or "image"
+	Descending    bool                   `protobuf:"varint,7,opt,name=descending,proto3" json:"descending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListJobsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+// What ListJobs shows of a job; registry credentials are never returned
+type JobSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Image         string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Schedule      string                 `protobuf:"bytes,4,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	NextRun       int64                  `protobuf:"varint,6,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`       // Unix seconds, 0 if none
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix seconds
+	Runs          int32                  `protobuf:"varint,8,opt,name=runs,proto3" json:"runs,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Paused        bool                   `protobuf:"varint,10,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobSummary) Reset() {
+	*x = JobSummary{}
+	mi := &file_proto_scheduler_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobSummary) ProtoMessage() {}
+
+func (x *JobSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobSummary.ProtoReflect.Descriptor instead.
+func (*JobSummary) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *JobSummary) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobSummary) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *JobSummary) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *JobSummary) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *JobSummary) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobSummary) GetNextRun() int64 {
+	if x != nil {
+		return x.NextRun
+	}
+	return 0
+}
+
+func (x *JobSummary) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *JobSummary) GetRuns() int32 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *JobSummary) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *JobSummary) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*JobSummary          `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListJobsResponse) GetJobs() []*JobSummary {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *ListJobsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type DeleteJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteJobRequest) Reset() {
+	*x = DeleteJobRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteJobRequest) ProtoMessage() {}
+
+func (x *DeleteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteJobRequest.ProtoReflect.Descriptor instead.
+func (*DeleteJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type UpdateJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`                                 // job.id selects the job to update
+	UpdateMask    []string               `protobuf:"bytes,2,rep,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"` // Job field names to copy from job, e.g. "image", "schedule". Unset fields are cleared
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateJobRequest) Reset() {
+	*x = UpdateJobRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateJobRequest) ProtoMessage() {}
+
+func (x *UpdateJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateJobRequest.ProtoReflect.Descriptor instead.
+func (*UpdateJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateJobRequest) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *UpdateJobRequest) GetUpdateMask() []string {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type PauseJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseJobRequest) Reset() {
+	*x = PauseJobRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseJobRequest) ProtoMessage() {}
+
+func (x *PauseJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseJobRequest.ProtoReflect.Descriptor instead.
+func (*PauseJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PauseJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ResumeJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeJobRequest) Reset() {
+	*x = ResumeJobRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeJobRequest) ProtoMessage() {}
+
+func (x *ResumeJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeJobRequest.ProtoReflect.Descriptor instead.
+func (*ResumeJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ResumeJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type QueueStats struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
-	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Command          string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
-	Schedule         string                 `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`
-	Image            string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
-	RegistryUsername string                 `protobuf:"bytes,5,opt,name=registry_username,json=registryUsername,proto3" json:"registry_username,omitempty"`
-	RegistryPassword string                 `protobuf:"bytes,6,opt,name=registry_password,json=registryPassword,proto3" json:"registry_password,omitempty"`
-	RegistryServer   string                 `protobuf:"bytes,7,opt,name=registry_server,json=registryServer,proto3" json:"registry_server,omitempty"`
+	Depth            int32                  `protobuf:"varint,1,opt,name=depth,proto3" json:"depth,omitempty"`                                                 // Runs waiting for a worker, including those handed out but not acked yet
+	Delivering       int32                  `protobuf:"varint,2,opt,name=delivering,proto3" json:"delivering,omitempty"`                                       // Runs handed to a worker that has not acked them yet
+	Delayed          int32                  `protobuf:"varint,5,opt,name=delayed,proto3" json:"delayed,omitempty"`                                             // Retries waiting out their backoff
+	OldestEnqueuedAt int64                  `protobuf:"varint,3,opt,name=oldest_enqueued_at,json=oldestEnqueuedAt,proto3" json:"oldest_enqueued_at,omitempty"` // Unix seconds, 0 if the queue is empty
+	OldestAgeSeconds int64                  `protobuf:"varint,4,opt,name=oldest_age_seconds,json=oldestAgeSeconds,proto3" json:"oldest_age_seconds,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
 
-func (x *Job) Reset() {
-	*x = Job{}
-	mi := &file_proto_scheduler_proto_msgTypes[0]
+func (x *QueueStats) Reset() {
+	*x = QueueStats{}
+	mi := &file_proto_scheduler_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStats) ProtoMessage() {}
+
+func (x *QueueStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStats.ProtoReflect.Descriptor instead.
+func (*QueueStats) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *QueueStats) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *QueueStats) GetDelivering() int32 {
+	if x != nil {
+		return x.Delivering
+	}
+	return 0
+}
+
+func (x *QueueStats) GetDelayed() int32 {
+	if x != nil {
+		return x.Delayed
+	}
+	return 0
+}
+
+func (x *QueueStats) GetOldestEnqueuedAt() int64 {
+	if x != nil {
+		return x.OldestEnqueuedAt
+	}
+	return 0
+}
+
+func (x *QueueStats) GetOldestAgeSeconds() int64 {
+	if x != nil {
+		return x.OldestAgeSeconds
+	}
+	return 0
+}
+
+type CancelRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunRequest) Reset() {
+	*x = CancelRunRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunRequest) ProtoMessage() {}
+
+func (x *CancelRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunRequest.ProtoReflect.Descriptor instead.
+func (*CancelRunRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CancelRunRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+// A run that failed for good, after any retries, parked until it is requeued
+type DeadLetter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Command       string                 `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Output        string                 `protobuf:"bytes,6,opt,name=output,proto3" json:"output,omitempty"`                               // Output of the last attempt, left out by ListDeadLetters
+	Attempts      int32                  `protobuf:"varint,7,opt,name=attempts,proto3" json:"attempts,omitempty"`                          // Attempts made
+	ExitCode      int32                  `protobuf:"varint,8,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`          // Of the last attempt, -1 if the container could not be run
+	ScheduledAt   int64                  `protobuf:"varint,9,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"` // Unix seconds
+	FailedAt      int64                  `protobuf:"varint,10,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_proto_scheduler_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeadLetter) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetScheduledAt() int64 {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+type ListDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Only dead letters of this job
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`             // Max dead letters returned (default 50)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListDeadLettersRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ListDeadLettersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+type GetDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeadLetterRequest) Reset() {
+	*x = GetDeadLetterRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeadLetterRequest) ProtoMessage() {}
+
+func (x *GetDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*GetDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetDeadLetterRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+// Selects the dead letters to requeue: one run, every run of a job, or all
+type RequeueDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	All           bool                   `protobuf:"varint,3,opt,name=all,proto3" json:"all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadLettersRequest) Reset() {
+	*x = RequeueDeadLettersRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadLettersRequest) ProtoMessage() {}
+
+func (x *RequeueDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RequeueDeadLettersRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *RequeueDeadLettersRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *RequeueDeadLettersRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type RequeueDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunIds        []string               `protobuf:"bytes,1,rep,name=run_ids,json=runIds,proto3" json:"run_ids,omitempty"` // Runs queued again
+	Skipped       []string               `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`             // "<run id>: <reason>" for runs that could not be
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadLettersResponse) Reset() {
+	*x = RequeueDeadLettersResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadLettersResponse) ProtoMessage() {}
+
+func (x *RequeueDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RequeueDeadLettersResponse) GetRunIds() []string {
+	if x != nil {
+		return x.RunIds
+	}
+	return nil
+}
+
+func (x *RequeueDeadLettersResponse) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// A worker known to the server, connected or not
+type Worker struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId       string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // "CONNECTED", "UNRESPONSIVE" (connected, but no heartbeat within the lease duration) or "DISCONNECTED"
+	Labels         map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Version        string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Slots          int32                  `protobuf:"varint,5,opt,name=slots,proto3" json:"slots,omitempty"`
+	Cpus           float64                `protobuf:"fixed64,6,opt,name=cpus,proto3" json:"cpus,omitempty"` // Capacity it advertised, 0 = unknown
+	MemoryMb       int64                  `protobuf:"varint,7,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	CpusUsed       float64                `protobuf:"fixed64,8,opt,name=cpus_used,json=cpusUsed,proto3" json:"cpus_used,omitempty"` // Requested by the runs it holds
+	MemoryUsedMb   int64                  `protobuf:"varint,9,opt,name=memory_used_mb,json=memoryUsedMb,proto3" json:"memory_used_mb,omitempty"`
+	RunIds         []string               `protobuf:"bytes,10,rep,name=run_ids,json=runIds,proto3" json:"run_ids,omitempty"`                 // Runs it holds
+	ConnectedAt    int64                  `protobuf:"varint,11,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"` // Start of its current or latest session
+	LastHeartbeat  int64                  `protobuf:"varint,12,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat,omitempty"`
+	DisconnectedAt int64                  `protobuf:"varint,13,opt,name=disconnected_at,json=disconnectedAt,proto3" json:"disconnected_at,omitempty"` // 0 while connected
+	Cordoned       bool                   `protobuf:"varint,14,opt,name=cordoned,proto3" json:"cordoned,omitempty"`                                   // Gets no new runs until uncordoned, also across reconnects
+	Draining       bool                   `protobuf:"varint,15,opt,name=draining,proto3" json:"draining,omitempty"`                                   // Gets no new runs and is disconnected once it holds none
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Worker) Reset() {
+	*x = Worker{}
+	mi := &file_proto_scheduler_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Worker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Worker) ProtoMessage() {}
+
+func (x *Worker) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Worker.ProtoReflect.Descriptor instead.
+func (*Worker) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Worker) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *Worker) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Worker) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Worker) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Worker) GetSlots() int32 {
+	if x != nil {
+		return x.Slots
+	}
+	return 0
+}
+
+func (x *Worker) GetCpus() float64 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *Worker) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *Worker) GetCpusUsed() float64 {
+	if x != nil {
+		return x.CpusUsed
+	}
+	return 0
+}
+
+func (x *Worker) GetMemoryUsedMb() int64 {
+	if x != nil {
+		return x.MemoryUsedMb
+	}
+	return 0
+}
+
+func (x *Worker) GetRunIds() []string {
+	if x != nil {
+		return x.RunIds
+	}
+	return nil
+}
+
+func (x *Worker) GetConnectedAt() int64 {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return 0
+}
+
+func (x *Worker) GetLastHeartbeat() int64 {
+	if x != nil {
+		return x.LastHeartbeat
+	}
+	return 0
+}
+
+func (x *Worker) GetDisconnectedAt() int64 {
+	if x != nil {
+		return x.DisconnectedAt
+	}
+	return 0
+}
+
+func (x *Worker) GetCordoned() bool {
+	if x != nil {
+		return x.Cordoned
+	}
+	return false
+}
+
+func (x *Worker) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+type ListWorkersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // Only workers with this status, case-insensitive. Empty = all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkersRequest) Reset() {
+	*x = ListWorkersRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkersRequest) ProtoMessage() {}
+
+func (x *ListWorkersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkersRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListWorkersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListWorkersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       []*Worker              `protobuf:"bytes,1,rep,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkersResponse) Reset() {
+	*x = ListWorkersResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkersResponse) ProtoMessage() {}
+
+func (x *ListWorkersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkersResponse.ProtoReflect.Descriptor instead.
+func (*ListWorkersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListWorkersResponse) GetWorkers() []*Worker {
+	if x != nil {
+		return x.Workers
+	}
+	return nil
+}
+
+type GetWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkerRequest) Reset() {
+	*x = GetWorkerRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkerRequest) ProtoMessage() {}
+
+func (x *GetWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkerRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type CordonWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CordonWorkerRequest) Reset() {
+	*x = CordonWorkerRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CordonWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CordonWorkerRequest) ProtoMessage() {}
+
+func (x *CordonWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CordonWorkerRequest.ProtoReflect.Descriptor instead.
+func (*CordonWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CordonWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type UncordonWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UncordonWorkerRequest) Reset() {
+	*x = UncordonWorkerRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UncordonWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UncordonWorkerRequest) ProtoMessage() {}
+
+func (x *UncordonWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UncordonWorkerRequest.ProtoReflect.Descriptor instead.
+func (*UncordonWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UncordonWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type DrainWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainWorkerRequest) Reset() {
+	*x = DrainWorkerRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainWorkerRequest) ProtoMessage() {}
+
+func (x *DrainWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainWorkerRequest.ProtoReflect.Descriptor instead.
+func (*DrainWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DrainWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+// Sent by the server down a worker's ConnectWorker stream
+type ServerMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*ServerMessage_Job
+	//	*ServerMessage_Cancel
+	//	*ServerMessage_Leases
+	//	*ServerMessage_Disconnect
+	Message       isServerMessage_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerMessage) Reset() {
+	*x = ServerMessage{}
+	mi := &file_proto_scheduler_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Job) String() string {
+func (x *ServerMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Job) ProtoMessage() {}
+func (*ServerMessage) ProtoMessage() {}
 
-func (x *Job) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[0]
+func (x *ServerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -59,85 +2824,104 @@ func (x *Job) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Job.ProtoReflect.Descriptor instead.
-func (*Job) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use ServerMessage.ProtoReflect.Descriptor instead.
+func (*ServerMessage) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *Job) GetId() string {
+func (x *ServerMessage) GetMessage() isServerMessage_Message {
 	if x != nil {
-		return x.Id
+		return x.Message
 	}
-	return ""
+	return nil
 }
 
-func (x *Job) GetCommand() string {
+func (x *ServerMessage) GetJob() *Job {
 	if x != nil {
-		return x.Command
+		if x, ok := x.Message.(*ServerMessage_Job); ok {
+			return x.Job
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *Job) GetSchedule() string {
+func (x *ServerMessage) GetCancel() *CancelRunRequest {
 	if x != nil {
-		return x.Schedule
+		if x, ok := x.Message.(*ServerMessage_Cancel); ok {
+			return x.Cancel
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *Job) GetImage() string {
+func (x *ServerMessage) GetLeases() *LeaseGrant {
 	if x != nil {
-		return x.Image
+		if x, ok := x.Message.(*ServerMessage_Leases); ok {
+			return x.Leases
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *Job) GetRegistryUsername() string {
+func (x *ServerMessage) GetDisconnect() *Disconnect {
 	if x != nil {
-		return x.RegistryUsername
+		if x, ok := x.Message.(*ServerMessage_Disconnect); ok {
+			return x.Disconnect
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *Job) GetRegistryPassword() string {
-	if x != nil {
-		return x.RegistryPassword
-	}
-	return ""
+type isServerMessage_Message interface {
+	isServerMessage_Message()
 }
 
-func (x *Job) GetRegistryServer() string {
-	if x != nil {
-		return x.RegistryServer
-	}
-	return ""
+type ServerMessage_Job struct {
+	Job *Job `protobuf:"bytes,1,opt,name=job,proto3,oneof"` // A run to execute, to be acked
 }
 
-// Worker sends this to the server
-type JobResponse struct {
+type ServerMessage_Cancel struct {
+	Cancel *CancelRunRequest `protobuf:"bytes,2,opt,name=cancel,proto3,oneof"` // Stop the run and remove its container
+}
+
+type ServerMessage_Leases struct {
+	Leases *LeaseGrant `protobuf:"bytes,3,opt,name=leases,proto3,oneof"` // Leases granted or renewed
+}
+
+type ServerMessage_Disconnect struct {
+	Disconnect *Disconnect `protobuf:"bytes,4,opt,name=disconnect,proto3,oneof"` // The worker was drained and holds no runs: it should disconnect
+}
+
+func (*ServerMessage_Job) isServerMessage_Message() {}
+
+func (*ServerMessage_Cancel) isServerMessage_Message() {}
+
+func (*ServerMessage_Leases) isServerMessage_Message() {}
+
+func (*ServerMessage_Disconnect) isServerMessage_Message() {}
+
+type Disconnect struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobResponse) Reset() {
-	*x = JobResponse{}
-	mi := &file_proto_scheduler_proto_msgTypes[1]
+func (x *Disconnect) Reset() {
+	*x = Disconnect{}
+	mi := &file_proto_scheduler_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobResponse) String() string {
+func (x *Disconnect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobResponse) ProtoMessage() {}
+func (*Disconnect) ProtoMessage() {}
 
-func (x *JobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[1]
+func (x *Disconnect) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -148,56 +2932,47 @@ func (x *JobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobResponse.ProtoReflect.Descriptor instead.
-func (*JobResponse) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *JobResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
-}
-
-func (x *JobResponse) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
+// Deprecated: Use Disconnect.ProtoReflect.Descriptor instead.
+func (*Disconnect) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *JobResponse) GetId() string {
+func (x *Disconnect) GetReason() string {
 	if x != nil {
-		return x.Id
+		return x.Reason
 	}
 	return ""
 }
 
-type JobStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // "QUEUED", "RUNNING", "COMPLETED", "FAILED"
-	Output        string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"` // The logs (e.g., "Hello from Docker")
+// Sent by a worker up its ConnectWorker stream
+type WorkerMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*WorkerMessage_Hello
+	//	*WorkerMessage_Ack
+	//	*WorkerMessage_Heartbeat
+	//	*WorkerMessage_Drain
+	Message       isWorkerMessage_Message `protobuf_oneof:"message"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobStatusResponse) Reset() {
-	*x = JobStatusResponse{}
-	mi := &file_proto_scheduler_proto_msgTypes[2]
+func (x *WorkerMessage) Reset() {
+	*x = WorkerMessage{}
+	mi := &file_proto_scheduler_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobStatusResponse) String() string {
+func (x *WorkerMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatusResponse) ProtoMessage() {}
+func (*WorkerMessage) ProtoMessage() {}
 
-func (x *JobStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[2]
+func (x *WorkerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,54 +2983,103 @@ func (x *JobStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatusResponse.ProtoReflect.Descriptor instead.
-func (*JobStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use WorkerMessage.ProtoReflect.Descriptor instead.
+func (*WorkerMessage) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *JobStatusResponse) GetJobId() string {
+func (x *WorkerMessage) GetMessage() isWorkerMessage_Message {
 	if x != nil {
-		return x.JobId
+		return x.Message
 	}
-	return ""
+	return nil
 }
 
-func (x *JobStatusResponse) GetStatus() string {
+func (x *WorkerMessage) GetHello() *WorkerHello {
 	if x != nil {
-		return x.Status
+		if x, ok := x.Message.(*WorkerMessage_Hello); ok {
+			return x.Hello
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *JobStatusResponse) GetOutput() string {
+func (x *WorkerMessage) GetAck() *Ack {
 	if x != nil {
-		return x.Output
+		if x, ok := x.Message.(*WorkerMessage_Ack); ok {
+			return x.Ack
+		}
 	}
-	return ""
+	return nil
 }
 
-type JobStatusRequest struct {
+func (x *WorkerMessage) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		if x, ok := x.Message.(*WorkerMessage_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *WorkerMessage) GetDrain() *Drain {
+	if x != nil {
+		if x, ok := x.Message.(*WorkerMessage_Drain); ok {
+			return x.Drain
+		}
+	}
+	return nil
+}
+
+type isWorkerMessage_Message interface {
+	isWorkerMessage_Message()
+}
+
+type WorkerMessage_Hello struct {
+	Hello *WorkerHello `protobuf:"bytes,1,opt,name=hello,proto3,oneof"` // First message of the stream
+}
+
+type WorkerMessage_Ack struct {
+	Ack *Ack `protobuf:"bytes,2,opt,name=ack,proto3,oneof"` // The worker received a run
+}
+
+type WorkerMessage_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,3,opt,name=heartbeat,proto3,oneof"`
+}
+
+type WorkerMessage_Drain struct {
+	Drain *Drain `protobuf:"bytes,4,opt,name=drain,proto3,oneof"` // The worker is shutting down: send it no more runs, and disconnect it once idle
+}
+
+func (*WorkerMessage_Hello) isWorkerMessage_Message() {}
+
+func (*WorkerMessage_Ack) isWorkerMessage_Message() {}
+
+func (*WorkerMessage_Heartbeat) isWorkerMessage_Message() {}
+
+func (*WorkerMessage_Drain) isWorkerMessage_Message() {}
+
+type Drain struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobStatusRequest) Reset() {
-	*x = JobStatusRequest{}
-	mi := &file_proto_scheduler_proto_msgTypes[3]
+func (x *Drain) Reset() {
+	*x = Drain{}
+	mi := &file_proto_scheduler_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobStatusRequest) String() string {
+func (x *Drain) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatusRequest) ProtoMessage() {}
+func (*Drain) ProtoMessage() {}
 
-func (x *JobStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[3]
+func (x *Drain) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -266,41 +3090,33 @@ func (x *JobStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatusRequest.ProtoReflect.Descriptor instead.
-func (*JobStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *JobStatusRequest) GetJobId() string {
-	if x != nil {
-		return x.JobId
-	}
-	return ""
+// Deprecated: Use Drain.ProtoReflect.Descriptor instead.
+func (*Drain) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{41}
 }
 
-type WorkerHello struct {
+type Ack struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`  // e.g., "worker-1"
-	MemoryMb      int32                  `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"` // e.g., 2048
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WorkerHello) Reset() {
-	*x = WorkerHello{}
-	mi := &file_proto_scheduler_proto_msgTypes[4]
+func (x *Ack) Reset() {
+	*x = Ack{}
+	mi := &file_proto_scheduler_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WorkerHello) String() string {
+func (x *Ack) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerHello) ProtoMessage() {}
+func (*Ack) ProtoMessage() {}
 
-func (x *WorkerHello) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[4]
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -311,50 +3127,41 @@ func (x *WorkerHello) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerHello.ProtoReflect.Descriptor instead.
-func (*WorkerHello) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *WorkerHello) GetWorkerId() string {
+func (x *Ack) GetRunId() string {
 	if x != nil {
-		return x.WorkerId
+		return x.RunId
 	}
 	return ""
 }
 
-func (x *WorkerHello) GetMemoryMb() int32 {
-	if x != nil {
-		return x.MemoryMb
-	}
-	return 0
-}
-
-// Sent by Worker ONLY when finished
-type JobResult struct {
+// Sent periodically; the leases of the runs listed are renewed
+type Heartbeat struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"` // True = Exit Code 0, False = Crashed
-	Output        string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`
+	RunIds        []string               `protobuf:"bytes,1,rep,name=run_ids,json=runIds,proto3" json:"run_ids,omitempty"` // Runs the worker holds, executing or waiting their turn
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobResult) Reset() {
-	*x = JobResult{}
-	mi := &file_proto_scheduler_proto_msgTypes[5]
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_proto_scheduler_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobResult) String() string {
+func (x *Heartbeat) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobResult) ProtoMessage() {}
+func (*Heartbeat) ProtoMessage() {}
 
-func (x *JobResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[5]
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -365,53 +3172,94 @@ func (x *JobResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobResult.ProtoReflect.Descriptor instead.
-func (*JobResult) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *JobResult) GetJobId() string {
+func (x *Heartbeat) GetRunIds() []string {
 	if x != nil {
-		return x.JobId
+		return x.RunIds
 	}
-	return ""
+	return nil
 }
 
-func (x *JobResult) GetSuccess() bool {
+// A worker holds a run until its lease expires. A run whose lease is not
+// renewed in time is taken back and dispatched again
+type Lease struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lease) Reset() {
+	*x = Lease{}
+	mi := &file_proto_scheduler_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lease) ProtoMessage() {}
+
+func (x *Lease) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[44]
 	if x != nil {
-		return x.Success
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *JobResult) GetOutput() string {
+// Deprecated: Use Lease.ProtoReflect.Descriptor instead.
+func (*Lease) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *Lease) GetRunId() string {
 	if x != nil {
-		return x.Output
+		return x.RunId
 	}
 	return ""
 }
 
-type Empty struct {
+func (x *Lease) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type LeaseGrant struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Leases        []*Lease               `protobuf:"bytes,1,rep,name=leases,proto3" json:"leases,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_proto_scheduler_proto_msgTypes[6]
+func (x *LeaseGrant) Reset() {
+	*x = LeaseGrant{}
+	mi := &file_proto_scheduler_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *LeaseGrant) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*LeaseGrant) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[6]
+func (x *LeaseGrant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -422,16 +3270,23 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use LeaseGrant.ProtoReflect.Descriptor instead.
+func (*LeaseGrant) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *LeaseGrant) GetLeases() []*Lease {
+	if x != nil {
+		return x.Leases
+	}
+	return nil
 }
 
 var File_proto_scheduler_proto protoreflect.FileDescriptor
 
 const file_proto_scheduler_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/scheduler.proto\x12\tscheduler\"\xe4\x01\n" +
+	"\x15proto/scheduler.proto\x12\tscheduler\"\xf8\t\n" +
 	"\x03Job\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
 	"\acommand\x18\x02 \x01(\tR\acommand\x12\x1a\n" +
@@ -439,30 +3294,315 @@ const file_proto_scheduler_proto_rawDesc = "" +
 	"\x05image\x18\x04 \x01(\tR\x05image\x12+\n" +
 	"\x11registry_username\x18\x05 \x01(\tR\x10registryUsername\x12+\n" +
 	"\x11registry_password\x18\x06 \x01(\tR\x10registryPassword\x12'\n" +
-	"\x0fregistry_server\x18\a \x01(\tR\x0eregistryServer\"Q\n" +
+	"\x0fregistry_server\x18\a \x01(\tR\x0eregistryServer\x12\x1a\n" +
+	"\btimezone\x18\b \x01(\tR\btimezone\x12%\n" +
+	"\x0emisfire_policy\x18\t \x01(\tR\rmisfirePolicy\x12#\n" +
+	"\rmisfire_limit\x18\n" +
+	" \x01(\x05R\fmisfireLimit\x12\x15\n" +
+	"\x06run_at\x18\v \x01(\tR\x05runAt\x12\x15\n" +
+	"\x06run_in\x18\f \x01(\tR\x05runIn\x12\x1d\n" +
+	"\n" +
+	"not_before\x18\r \x01(\tR\tnotBefore\x12\x1b\n" +
+	"\tnot_after\x18\x0e \x01(\tR\bnotAfter\x12\x19\n" +
+	"\bmax_runs\x18\x0f \x01(\x05R\amaxRuns\x12-\n" +
+	"\x12concurrency_policy\x18\x10 \x01(\tR\x11concurrencyPolicy\x12\x15\n" +
+	"\x06run_id\x18\x11 \x01(\tR\x05runId\x12\x16\n" +
+	"\x06spread\x18\x12 \x01(\bR\x06spread\x12%\n" +
+	"\x0ejitter_seconds\x18\x13 \x01(\x05R\rjitterSeconds\x122\n" +
+	"\x06labels\x18\x14 \x03(\v2\x1a.scheduler.Job.LabelsEntryR\x06labels\x12,\n" +
+	"\x05retry\x18\x15 \x01(\v2\x16.scheduler.RetryPolicyR\x05retry\x12\x18\n" +
+	"\atimeout\x18\x16 \x01(\tR\atimeout\x12\x12\n" +
+	"\x04cpus\x18\x17 \x01(\x01R\x04cpus\x12\x1b\n" +
+	"\tmemory_mb\x18\x18 \x01(\x05R\bmemoryMb\x121\n" +
+	"\x06limits\x18\x19 \x01(\v2\x19.scheduler.ResourceLimitsR\x06limits\x12E\n" +
+	"\rnode_selector\x18\x1a \x03(\v2 .scheduler.Job.NodeSelectorEntryR\fnodeSelector\x128\n" +
+	"\baffinity\x18\x1b \x03(\v2\x1c.scheduler.Job.AffinityEntryR\baffinity\x12E\n" +
+	"\ranti_affinity\x18\x1c \x03(\v2 .scheduler.Job.AntiAffinityEntryR\fantiAffinity\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a?\n" +
+	"\x11NodeSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
+	"\rAffinityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a?\n" +
+	"\x11AntiAffinityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc7\x01\n" +
+	"\x0eResourceLimits\x12\x1b\n" +
+	"\tmemory_mb\x18\x01 \x01(\x03R\bmemoryMb\x12$\n" +
+	"\x0ememory_swap_mb\x18\x02 \x01(\x03R\fmemorySwapMb\x12\x12\n" +
+	"\x04cpus\x18\x03 \x01(\x01R\x04cpus\x12\x1d\n" +
+	"\n" +
+	"cpu_shares\x18\x04 \x01(\x03R\tcpuShares\x12\x12\n" +
+	"\x04pids\x18\x05 \x01(\x03R\x04pids\x12+\n" +
+	"\aulimits\x18\x06 \x03(\v2\x11.scheduler.UlimitR\aulimits\"D\n" +
+	"\x06Ulimit\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04soft\x18\x02 \x01(\x03R\x04soft\x12\x12\n" +
+	"\x04hard\x18\x03 \x01(\x03R\x04hard\"\xc9\x01\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x12'\n" +
+	"\x0finitial_backoff\x18\x02 \x01(\tR\x0einitialBackoff\x12\x1e\n" +
+	"\n" +
+	"multiplier\x18\x03 \x01(\x01R\n" +
+	"multiplier\x12\x1f\n" +
+	"\vmax_backoff\x18\x04 \x01(\tR\n" +
+	"maxBackoff\x12-\n" +
+	"\x13retry_on_exit_codes\x18\x05 \x03(\x05R\x10retryOnExitCodes\"Q\n" +
 	"\vJobResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x0e\n" +
-	"\x02id\x18\x03 \x01(\tR\x02id\"Z\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\"\xf0\x02\n" +
 	"\x11JobStatusResponse\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
-	"\x06output\x18\x03 \x01(\tR\x06output\")\n" +
+	"\x06output\x18\x03 \x01(\tR\x06output\x12.\n" +
+	"\bmisfires\x18\x04 \x03(\v2\x12.scheduler.MisfireR\bmisfires\x12#\n" +
+	"\rmisfire_count\x18\x05 \x01(\x05R\fmisfireCount\x12\x19\n" +
+	"\bnext_run\x18\x06 \x01(\x03R\anextRun\x12\x12\n" +
+	"\x04runs\x18\a \x01(\x05R\x04runs\x12%\n" +
+	"\x0eretired_reason\x18\b \x01(\tR\rretiredReason\x12\x16\n" +
+	"\x06paused\x18\t \x01(\bR\x06paused\x12\x15\n" +
+	"\x06run_id\x18\n" +
+	" \x01(\tR\x05runId\x12\x1b\n" +
+	"\tworker_id\x18\v \x01(\tR\bworkerId\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\f \x01(\x03R\tstartedAt\"D\n" +
+	"\aMisfire\x12!\n" +
+	"\fscheduled_at\x18\x01 \x01(\x03R\vscheduledAt\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\")\n" +
 	"\x10JobStatusRequest\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"G\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x82\x02\n" +
 	"\vWorkerHello\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1b\n" +
-	"\tmemory_mb\x18\x02 \x01(\x05R\bmemoryMb\"T\n" +
+	"\tmemory_mb\x18\x02 \x01(\x05R\bmemoryMb\x12\x14\n" +
+	"\x05slots\x18\x03 \x01(\x05R\x05slots\x12\x12\n" +
+	"\x04cpus\x18\x04 \x01(\x01R\x04cpus\x12:\n" +
+	"\x06labels\x18\x05 \x03(\v2\".scheduler.WorkerHello.LabelsEntryR\x06labels\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\tR\aversion\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb9\x02\n" +
 	"\tJobResult\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x16\n" +
-	"\x06output\x18\x03 \x01(\tR\x06output\"\a\n" +
-	"\x05Empty2\xfd\x01\n" +
+	"\x06output\x18\x03 \x01(\tR\x06output\x12\x15\n" +
+	"\x06run_id\x18\x04 \x01(\tR\x05runId\x12\x1b\n" +
+	"\texit_code\x18\x05 \x01(\x05R\bexitCode\x12\x1b\n" +
+	"\ttimed_out\x18\x06 \x01(\bR\btimedOut\x12\x1d\n" +
+	"\n" +
+	"oom_killed\x18\a \x01(\bR\toomKilled\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\t \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\n" +
+	" \x01(\x03R\n" +
+	"finishedAt\x12\x1b\n" +
+	"\tworker_id\x18\v \x01(\tR\bworkerId\"\a\n" +
+	"\x05Empty\"\xea\x03\n" +
+	"\x03Run\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12!\n" +
+	"\fscheduled_at\x18\x05 \x01(\x03R\vscheduledAt\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\a \x01(\x03R\n" +
+	"finishedAt\x12\x1b\n" +
+	"\texit_code\x18\b \x01(\x05R\bexitCode\x12\x16\n" +
+	"\x06output\x18\t \x01(\tR\x06output\x12\x18\n" +
+	"\aattempt\x18\n" +
+	" \x01(\x05R\aattempt\x121\n" +
+	"\battempts\x18\v \x03(\v2\x15.scheduler.RunAttemptR\battempts\x12\x1d\n" +
+	"\n" +
+	"oom_killed\x18\f \x01(\bR\toomKilled\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\x120\n" +
+	"\x14container_started_at\x18\x0e \x01(\x03R\x12containerStartedAt\x122\n" +
+	"\x15container_finished_at\x18\x0f \x01(\x03R\x13containerFinishedAt\"\xd7\x01\n" +
+	"\n" +
+	"RunAttempt\x12\x18\n" +
+	"\aattempt\x18\x01 \x01(\x05R\aattempt\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x04 \x01(\x03R\n" +
+	"finishedAt\x12\x1b\n" +
+	"\texit_code\x18\x05 \x01(\x05R\bexitCode\x12\x1d\n" +
+	"\n" +
+	"oom_killed\x18\x06 \x01(\bR\toomKilled\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\">\n" +
+	"\x0fListRunsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"6\n" +
+	"\x10ListRunsResponse\x12\"\n" +
+	"\x04runs\x18\x01 \x03(\v2\x0e.scheduler.RunR\x04runs\"&\n" +
+	"\rGetRunRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"\xce\x01\n" +
+	"\x0fListJobsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x16\n" +
+	"\x06labels\x18\x05 \x03(\tR\x06labels\x12\x19\n" +
+	"\border_by\x18\x06 \x01(\tR\aorderBy\x12\x1e\n" +
+	"\n" +
+	"descending\x18\a \x01(\bR\n" +
+	"descending\"\xe3\x02\n" +
+	"\n" +
+	"JobSummary\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x14\n" +
+	"\x05image\x18\x02 \x01(\tR\x05image\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x1a\n" +
+	"\bschedule\x18\x04 \x01(\tR\bschedule\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x19\n" +
+	"\bnext_run\x18\x06 \x01(\x03R\anextRun\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x12\n" +
+	"\x04runs\x18\b \x01(\x05R\x04runs\x129\n" +
+	"\x06labels\x18\t \x03(\v2!.scheduler.JobSummary.LabelsEntryR\x06labels\x12\x16\n" +
+	"\x06paused\x18\n" +
+	" \x01(\bR\x06paused\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"e\n" +
+	"\x10ListJobsResponse\x12)\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x15.scheduler.JobSummaryR\x04jobs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\")\n" +
+	"\x10DeleteJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"U\n" +
+	"\x10UpdateJobRequest\x12 \n" +
+	"\x03job\x18\x01 \x01(\v2\x0e.scheduler.JobR\x03job\x12\x1f\n" +
+	"\vupdate_mask\x18\x02 \x03(\tR\n" +
+	"updateMask\"(\n" +
+	"\x0fPauseJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\")\n" +
+	"\x10ResumeJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xb8\x01\n" +
+	"\n" +
+	"QueueStats\x12\x14\n" +
+	"\x05depth\x18\x01 \x01(\x05R\x05depth\x12\x1e\n" +
+	"\n" +
+	"delivering\x18\x02 \x01(\x05R\n" +
+	"delivering\x12\x18\n" +
+	"\adelayed\x18\x05 \x01(\x05R\adelayed\x12,\n" +
+	"\x12oldest_enqueued_at\x18\x03 \x01(\x03R\x10oldestEnqueuedAt\x12,\n" +
+	"\x12oldest_age_seconds\x18\x04 \x01(\x03R\x10oldestAgeSeconds\")\n" +
+	"\x10CancelRunRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"\x93\x02\n" +
+	"\n" +
+	"DeadLetter\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12\x18\n" +
+	"\acommand\x18\x04 \x01(\tR\acommand\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06output\x18\x06 \x01(\tR\x06output\x12\x1a\n" +
+	"\battempts\x18\a \x01(\x05R\battempts\x12\x1b\n" +
+	"\texit_code\x18\b \x01(\x05R\bexitCode\x12!\n" +
+	"\fscheduled_at\x18\t \x01(\x03R\vscheduledAt\x12\x1b\n" +
+	"\tfailed_at\x18\n" +
+	" \x01(\x03R\bfailedAt\"E\n" +
+	"\x16ListDeadLettersRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"S\n" +
+	"\x17ListDeadLettersResponse\x128\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x15.scheduler.DeadLetterR\vdeadLetters\"-\n" +
+	"\x14GetDeadLetterRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"[\n" +
+	"\x19RequeueDeadLettersRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x10\n" +
+	"\x03all\x18\x03 \x01(\bR\x03all\"O\n" +
+	"\x1aRequeueDeadLettersResponse\x12\x17\n" +
+	"\arun_ids\x18\x01 \x03(\tR\x06runIds\x12\x18\n" +
+	"\askipped\x18\x02 \x03(\tR\askipped\"\x97\x04\n" +
+	"\x06Worker\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x125\n" +
+	"\x06labels\x18\x03 \x03(\v2\x1d.scheduler.Worker.LabelsEntryR\x06labels\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x14\n" +
+	"\x05slots\x18\x05 \x01(\x05R\x05slots\x12\x12\n" +
+	"\x04cpus\x18\x06 \x01(\x01R\x04cpus\x12\x1b\n" +
+	"\tmemory_mb\x18\a \x01(\x03R\bmemoryMb\x12\x1b\n" +
+	"\tcpus_used\x18\b \x01(\x01R\bcpusUsed\x12$\n" +
+	"\x0ememory_used_mb\x18\t \x01(\x03R\fmemoryUsedMb\x12\x17\n" +
+	"\arun_ids\x18\n" +
+	" \x03(\tR\x06runIds\x12!\n" +
+	"\fconnected_at\x18\v \x01(\x03R\vconnectedAt\x12%\n" +
+	"\x0elast_heartbeat\x18\f \x01(\x03R\rlastHeartbeat\x12'\n" +
+	"\x0fdisconnected_at\x18\r \x01(\x03R\x0edisconnectedAt\x12\x1a\n" +
+	"\bcordoned\x18\x0e \x01(\bR\bcordoned\x12\x1a\n" +
+	"\bdraining\x18\x0f \x01(\bR\bdraining\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\",\n" +
+	"\x12ListWorkersRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"B\n" +
+	"\x13ListWorkersResponse\x12+\n" +
+	"\aworkers\x18\x01 \x03(\v2\x11.scheduler.WorkerR\aworkers\"/\n" +
+	"\x10GetWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"2\n" +
+	"\x13CordonWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"4\n" +
+	"\x15UncordonWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"1\n" +
+	"\x12DrainWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"\xdf\x01\n" +
+	"\rServerMessage\x12\"\n" +
+	"\x03job\x18\x01 \x01(\v2\x0e.scheduler.JobH\x00R\x03job\x125\n" +
+	"\x06cancel\x18\x02 \x01(\v2\x1b.scheduler.CancelRunRequestH\x00R\x06cancel\x12/\n" +
+	"\x06leases\x18\x03 \x01(\v2\x15.scheduler.LeaseGrantH\x00R\x06leases\x127\n" +
+	"\n" +
+	"disconnect\x18\x04 \x01(\v2\x15.scheduler.DisconnectH\x00R\n" +
+	"disconnectB\t\n" +
+	"\amessage\"$\n" +
+	"\n" +
+	"Disconnect\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"\xce\x01\n" +
+	"\rWorkerMessage\x12.\n" +
+	"\x05hello\x18\x01 \x01(\v2\x16.scheduler.WorkerHelloH\x00R\x05hello\x12\"\n" +
+	"\x03ack\x18\x02 \x01(\v2\x0e.scheduler.AckH\x00R\x03ack\x124\n" +
+	"\theartbeat\x18\x03 \x01(\v2\x14.scheduler.HeartbeatH\x00R\theartbeat\x12(\n" +
+	"\x05drain\x18\x04 \x01(\v2\x10.scheduler.DrainH\x00R\x05drainB\t\n" +
+	"\amessage\"\a\n" +
+	"\x05Drain\"\x1c\n" +
+	"\x03Ack\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"$\n" +
+	"\tHeartbeat\x12\x17\n" +
+	"\arun_ids\x18\x01 \x03(\tR\x06runIds\"=\n" +
+	"\x05Lease\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"6\n" +
+	"\n" +
+	"LeaseGrant\x12(\n" +
+	"\x06leases\x18\x01 \x03(\v2\x10.scheduler.LeaseR\x06leases2\xa7\v\n" +
 	"\tScheduler\x123\n" +
-	"\tSubmitJob\x12\x0e.scheduler.Job\x1a\x16.scheduler.JobResponse\x129\n" +
-	"\rConnectWorker\x12\x16.scheduler.WorkerHello\x1a\x0e.scheduler.Job0\x01\x125\n" +
+	"\tSubmitJob\x12\x0e.scheduler.Job\x1a\x16.scheduler.JobResponse\x12G\n" +
+	"\rConnectWorker\x12\x18.scheduler.WorkerMessage\x1a\x18.scheduler.ServerMessage(\x010\x01\x125\n" +
 	"\vCompleteJob\x12\x14.scheduler.JobResult\x1a\x10.scheduler.Empty\x12I\n" +
-	"\fGetJobStatus\x12\x1b.scheduler.JobStatusRequest\x1a\x1c.scheduler.JobStatusResponseB\tZ\a./protob\x06proto3"
+	"\fGetJobStatus\x12\x1b.scheduler.JobStatusRequest\x1a\x1c.scheduler.JobStatusResponse\x12C\n" +
+	"\bListRuns\x12\x1a.scheduler.ListRunsRequest\x1a\x1b.scheduler.ListRunsResponse\x122\n" +
+	"\x06GetRun\x12\x18.scheduler.GetRunRequest\x1a\x0e.scheduler.Run\x12C\n" +
+	"\bListJobs\x12\x1a.scheduler.ListJobsRequest\x1a\x1b.scheduler.ListJobsResponse\x12@\n" +
+	"\tDeleteJob\x12\x1b.scheduler.DeleteJobRequest\x1a\x16.scheduler.JobResponse\x12@\n" +
+	"\tUpdateJob\x12\x1b.scheduler.UpdateJobRequest\x1a\x16.scheduler.JobResponse\x12>\n" +
+	"\bPauseJob\x12\x1a.scheduler.PauseJobRequest\x1a\x16.scheduler.JobResponse\x12@\n" +
+	"\tResumeJob\x12\x1b.scheduler.ResumeJobRequest\x1a\x16.scheduler.JobResponse\x12@\n" +
+	"\tCancelRun\x12\x1b.scheduler.CancelRunRequest\x1a\x16.scheduler.JobResponse\x128\n" +
+	"\rGetQueueStats\x12\x10.scheduler.Empty\x1a\x15.scheduler.QueueStats\x12X\n" +
+	"\x0fListDeadLetters\x12!.scheduler.ListDeadLettersRequest\x1a\".scheduler.ListDeadLettersResponse\x12G\n" +
+	"\rGetDeadLetter\x12\x1f.scheduler.GetDeadLetterRequest\x1a\x15.scheduler.DeadLetter\x12a\n" +
+	"\x12RequeueDeadLetters\x12$.scheduler.RequeueDeadLettersRequest\x1a%.scheduler.RequeueDeadLettersResponse\x12L\n" +
+	"\vListWorkers\x12\x1d.scheduler.ListWorkersRequest\x1a\x1e.scheduler.ListWorkersResponse\x12;\n" +
+	"\tGetWorker\x12\x1b.scheduler.GetWorkerRequest\x1a\x11.scheduler.Worker\x12A\n" +
+	"\fCordonWorker\x12\x1e.scheduler.CordonWorkerRequest\x1a\x11.scheduler.Worker\x12E\n" +
+	"\x0eUncordonWorker\x12 .scheduler.UncordonWorkerRequest\x1a\x11.scheduler.Worker\x12?\n" +
+	"\vDrainWorker\x12\x1d.scheduler.DrainWorkerRequest\x1a\x11.scheduler.WorkerB\tZ\a./protob\x06proto3"
 
 var (
 	file_proto_scheduler_proto_rawDescOnce sync.Once
@@ -476,30 +3616,136 @@ func file_proto_scheduler_proto_rawDescGZIP() []byte {
 	return file_proto_scheduler_proto_rawDescData
 }
 
-var file_proto_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 53)
 var file_proto_scheduler_proto_goTypes = []any{
-	(*Job)(nil),               // 0: scheduler.Job
-	(*JobResponse)(nil),       // 1: scheduler.JobResponse
-	(*JobStatusResponse)(nil), // 2: scheduler.JobStatusResponse
-	(*JobStatusRequest)(nil),  // 3: scheduler.JobStatusRequest
-	(*WorkerHello)(nil),       // 4: scheduler.WorkerHello
-	(*JobResult)(nil),         // 5: scheduler.JobResult
-	(*Empty)(nil),             // 6: scheduler.Empty
+	(*Job)(nil),                        // 0: scheduler.Job
+	(*ResourceLimits)(nil),             // 1: scheduler.ResourceLimits
+	(*Ulimit)(nil),                     // 2: scheduler.Ulimit
+	(*RetryPolicy)(nil),                // 3: scheduler.RetryPolicy
+	(*JobResponse)(nil),                // 4: scheduler.JobResponse
+	(*JobStatusResponse)(nil),          // 5: scheduler.JobStatusResponse
+	(*Misfire)(nil),                    // 6: scheduler.Misfire
+	(*JobStatusRequest)(nil),           // 7: scheduler.JobStatusRequest
+	(*WorkerHello)(nil),                // 8: scheduler.WorkerHello
+	(*JobResult)(nil),                  // 9: scheduler.JobResult
+	(*Empty)(nil),                      // 10: scheduler.Empty
+	(*Run)(nil),                        // 11: scheduler.Run
+	(*RunAttempt)(nil),                 // 12: scheduler.RunAttempt
+	(*ListRunsRequest)(nil),            // 13: scheduler.ListRunsRequest
+	(*ListRunsResponse)(nil),           // 14: scheduler.ListRunsResponse
+	(*GetRunRequest)(nil),              // 15: scheduler.GetRunRequest
+	(*ListJobsRequest)(nil),            // 16: scheduler.ListJobsRequest
+	(*JobSummary)(nil),                 // 17: scheduler.JobSummary
+	(*ListJobsResponse)(nil),           // 18: scheduler.ListJobsResponse
+	(*DeleteJobRequest)(nil),           // 19: scheduler.DeleteJobRequest
+	(*UpdateJobRequest)(nil),           // 20: scheduler.UpdateJobRequest
+	(*PauseJobRequest)(nil),            // 21: scheduler.PauseJobRequest
+	(*ResumeJobRequest)(nil),           // 22: scheduler.ResumeJobRequest
+	(*QueueStats)(nil),                 // 23: scheduler.QueueStats
+	(*CancelRunRequest)(nil),           // 24: scheduler.CancelRunRequest
+	(*DeadLetter)(nil),                 // 25: scheduler.DeadLetter
+	(*ListDeadLettersRequest)(nil),     // 26: scheduler.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),    // 27: scheduler.ListDeadLettersResponse
+	(*GetDeadLetterRequest)(nil),       // 28: scheduler.GetDeadLetterRequest
+	(*RequeueDeadLettersRequest)(nil),  // 29: scheduler.RequeueDeadLettersRequest
+	(*RequeueDeadLettersResponse)(nil), // 30: scheduler.RequeueDeadLettersResponse
+	(*Worker)(nil),                     // 31: scheduler.Worker
+	(*ListWorkersRequest)(nil),         // 32: scheduler.ListWorkersRequest
+	(*ListWorkersResponse)(nil),        // 33: scheduler.ListWorkersResponse
+	(*GetWorkerRequest)(nil),           // 34: scheduler.GetWorkerRequest
+	(*CordonWorkerRequest)(nil),        // 35: scheduler.CordonWorkerRequest
+	(*UncordonWorkerRequest)(nil),      // 36: scheduler.UncordonWorkerRequest
+	(*DrainWorkerRequest)(nil),         // 37: scheduler.DrainWorkerRequest
+	(*ServerMessage)(nil),              // 38: scheduler.ServerMessage
+	(*Disconnect)(nil),                 // 39: scheduler.Disconnect
+	(*WorkerMessage)(nil),              // 40: scheduler.WorkerMessage
+	(*Drain)(nil),                      // 41: scheduler.Drain
+	(*Ack)(nil),                        // 42: scheduler.Ack
+	(*Heartbeat)(nil),                  // 43: scheduler.Heartbeat
+	(*Lease)(nil),                      // 44: scheduler.Lease
+	(*LeaseGrant)(nil),                 // 45: scheduler.LeaseGrant
+	nil,                                // 46: scheduler.Job.LabelsEntry
+	nil,                                // 47: scheduler.Job.NodeSelectorEntry
+	nil,                                // 48: scheduler.Job.AffinityEntry
+	nil,                                // 49: scheduler.Job.AntiAffinityEntry
+	nil,                                // 50: scheduler.WorkerHello.LabelsEntry
+	nil,                                // 51: scheduler.JobSummary.LabelsEntry
+	nil,                                // 52: scheduler.Worker.LabelsEntry
 }
 var file_proto_scheduler_proto_depIdxs = []int32{
-	0, // 0: scheduler.Scheduler.SubmitJob:input_type -> scheduler.Job
-	4, // 1: scheduler.Scheduler.ConnectWorker:input_type -> scheduler.WorkerHello
-	5, // 2: scheduler.Scheduler.CompleteJob:input_type -> scheduler.JobResult
-	3, // 3: scheduler.Scheduler.GetJobStatus:input_type -> scheduler.JobStatusRequest
-	1, // 4: scheduler.Scheduler.SubmitJob:output_type -> scheduler.JobResponse
-	0, // 5: scheduler.Scheduler.ConnectWorker:output_type -> scheduler.Job
-	6, // 6: scheduler.Scheduler.CompleteJob:output_type -> scheduler.Empty
-	2, // 7: scheduler.Scheduler.GetJobStatus:output_type -> scheduler.JobStatusResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	46, // 0: scheduler.Job.labels:type_name -> scheduler.Job.LabelsEntry
+	3,  // 1: scheduler.Job.retry:type_name -> scheduler.RetryPolicy
+	1,  // 2: scheduler.Job.limits:type_name -> scheduler.ResourceLimits
+	47, // 3: scheduler.Job.node_selector:type_name -> scheduler.Job.NodeSelectorEntry
+	48, // 4: scheduler.Job.affinity:type_name -> scheduler.Job.AffinityEntry
+	49, // 5: scheduler.Job.anti_affinity:type_name -> scheduler.Job.AntiAffinityEntry
+	2,  // 6: scheduler.ResourceLimits.ulimits:type_name -> scheduler.Ulimit
+	6,  // 7: scheduler.JobStatusResponse.misfires:type_name -> scheduler.Misfire
+	50, // 8: scheduler.WorkerHello.labels:type_name -> scheduler.WorkerHello.LabelsEntry
+	12, // 9: scheduler.Run.attempts:type_name -> scheduler.RunAttempt
+	11, // 10: scheduler.ListRunsResponse.runs:type_name -> scheduler.Run
+	51, // 11: scheduler.JobSummary.labels:type_name -> scheduler.JobSummary.LabelsEntry
+	17, // 12: scheduler.ListJobsResponse.jobs:type_name -> scheduler.JobSummary
+	0,  // 13: scheduler.UpdateJobRequest.job:type_name -> scheduler.Job
+	25, // 14: scheduler.ListDeadLettersResponse.dead_letters:type_name -> scheduler.DeadLetter
+	52, // 15: scheduler.Worker.labels:type_name -> scheduler.Worker.LabelsEntry
+	31, // 16: scheduler.ListWorkersResponse.workers:type_name -> scheduler.Worker
+	0,  // 17: scheduler.ServerMessage.job:type_name -> scheduler.Job
+	24, // 18: scheduler.ServerMessage.cancel:type_name -> scheduler.CancelRunRequest
+	45, // 19: scheduler.ServerMessage.leases:type_name -> scheduler.LeaseGrant
+	39, // 20: scheduler.ServerMessage.disconnect:type_name -> scheduler.Disconnect
+	8,  // 21: scheduler.WorkerMessage.hello:type_name -> scheduler.WorkerHello
+	42, // 22: scheduler.WorkerMessage.ack:type_name -> scheduler.Ack
+	43, // 23: scheduler.WorkerMessage.heartbeat:type_name -> scheduler.Heartbeat
+	41, // 24: scheduler.WorkerMessage.drain:type_name -> scheduler.Drain
+	44, // 25: scheduler.LeaseGrant.leases:type_name -> scheduler.Lease
+	0,  // 26: scheduler.Scheduler.SubmitJob:input_type -> scheduler.Job
+	40, // 27: scheduler.Scheduler.ConnectWorker:input_type -> scheduler.WorkerMessage
+	9,  // 28: scheduler.Scheduler.CompleteJob:input_type -> scheduler.JobResult
+	7,  // 29: scheduler.Scheduler.GetJobStatus:input_type -> scheduler.JobStatusRequest
+	13, // 30: scheduler.Scheduler.ListRuns:input_type -> scheduler.ListRunsRequest
+	15, // 31: scheduler.Scheduler.GetRun:input_type -> scheduler.GetRunRequest
+	16, // 32: scheduler.Scheduler.ListJobs:input_type -> scheduler.ListJobsRequest
+	19, // 33: scheduler.Scheduler.DeleteJob:input_type -> scheduler.DeleteJobRequest
+	20, // 34: scheduler.Scheduler.UpdateJob:input_type -> scheduler.UpdateJobRequest
+	21, // 35: scheduler.Scheduler.PauseJob:input_type -> scheduler.PauseJobRequest
+	22, // 36: scheduler.Scheduler.ResumeJob:input_type -> scheduler.ResumeJobRequest
+	24, // 37: scheduler.Scheduler.CancelRun:input_type -> scheduler.CancelRunRequest
+	10, // 38: scheduler.Scheduler.GetQueueStats:input_type -> scheduler.Empty
+	26, // 39: scheduler.Scheduler.ListDeadLetters:input_type -> scheduler.ListDeadLettersRequest
+	28, // 40: scheduler.Scheduler.GetDeadLetter:input_type -> scheduler.GetDeadLetterRequest
+	29, // 41: scheduler.Scheduler.RequeueDeadLetters:input_type -> scheduler.RequeueDeadLettersRequest
+	32, // 42: scheduler.Scheduler.ListWorkers:input_type -> scheduler.ListWorkersRequest
+	34, // 43: scheduler.Scheduler.GetWorker:input_type -> scheduler.GetWorkerRequest
+	35, // 44: scheduler.Scheduler.CordonWorker:input_type -> scheduler.CordonWorkerRequest
+	36, // 45: scheduler.Scheduler.UncordonWorker:input_type -> scheduler.UncordonWorkerRequest
+	37, // 46: scheduler.Scheduler.DrainWorker:input_type -> scheduler.DrainWorkerRequest
+	4,  // 47: scheduler.Scheduler.SubmitJob:output_type -> scheduler.JobResponse
+	38, // 48: scheduler.Scheduler.ConnectWorker:output_type -> scheduler.ServerMessage
+	10, // 49: scheduler.Scheduler.CompleteJob:output_type -> scheduler.Empty
+	5,  // 50: scheduler.Scheduler.GetJobStatus:output_type -> scheduler.JobStatusResponse
+	14, // 51: scheduler.Scheduler.ListRuns:output_type -> scheduler.ListRunsResponse
+	11, // 52: scheduler.Scheduler.GetRun:output_type -> scheduler.Run
+	18, // 53: scheduler.Scheduler.ListJobs:output_type -> scheduler.ListJobsResponse
+	4,  // 54: scheduler.Scheduler.DeleteJob:output_type -> scheduler.JobResponse
+	4,  // 55: scheduler.Scheduler.UpdateJob:output_type -> scheduler.JobResponse
+	4,  // 56: scheduler.Scheduler.PauseJob:output_type -> scheduler.JobResponse
+	4,  // 57: scheduler.Scheduler.ResumeJob:output_type -> scheduler.JobResponse
+	4,  // 58: scheduler.Scheduler.CancelRun:output_type -> scheduler.JobResponse
+	23, // 59: scheduler.Scheduler.GetQueueStats:output_type -> scheduler.QueueStats
+	27, // 60: scheduler.Scheduler.ListDeadLetters:output_type -> scheduler.ListDeadLettersResponse
+	25, // 61: scheduler.Scheduler.GetDeadLetter:output_type -> scheduler.DeadLetter
+	30, // 62: scheduler.Scheduler.RequeueDeadLetters:output_type -> scheduler.RequeueDeadLettersResponse
+	33, // 63: scheduler.Scheduler.ListWorkers:output_type -> scheduler.ListWorkersResponse
+	31, // 64: scheduler.Scheduler.GetWorker:output_type -> scheduler.Worker
+	31, // 65: scheduler.Scheduler.CordonWorker:output_type -> scheduler.Worker
+	31, // 66: scheduler.Scheduler.UncordonWorker:output_type -> scheduler.Worker
+	31, // 67: scheduler.Scheduler.DrainWorker:output_type -> scheduler.Worker
+	47, // [47:68] is the sub-list for method output_type
+	26, // [26:47] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_proto_scheduler_proto_init() }
@@ -507,13 +3753,25 @@ func file_proto_scheduler_proto_init() {
 	if File_proto_scheduler_proto != nil {
 		return
 	}
+	file_proto_scheduler_proto_msgTypes[38].OneofWrappers = []any{
+		(*ServerMessage_Job)(nil),
+		(*ServerMessage_Cancel)(nil),
+		(*ServerMessage_Leases)(nil),
+		(*ServerMessage_Disconnect)(nil),
+	}
+	file_proto_scheduler_proto_msgTypes[40].OneofWrappers = []any{
+		(*WorkerMessage_Hello)(nil),
+		(*WorkerMessage_Ack)(nil),
+		(*WorkerMessage_Heartbeat)(nil),
+		(*WorkerMessage_Drain)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_scheduler_proto_rawDesc), len(file_proto_scheduler_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   53,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// cancelRun marks a queued or running run CANCELLED and asks the worker
+// holding it, if any, to stop it. It returns a message for the client. Must
+// be called with store.mu held.
+func cancelRun(runId string) (string, error) {
+	jobId, _, err := parseRunId(runId)
+	if err != nil {
+		return "", err
+	}
+	run, err := LoadRun(runId, store.db)
+	if err != nil {
+		return "", err
+	}
+	if run.Status != "QUEUED" && run.Status != "RUNNING" && run.Status != "RETRYING" {
+		return "", fmt.Errorf("run is already %s", run.Status)
+	}
+
+	sent, err := stopRun(jobId, run, "CANCELLED")
+	if err != nil {
+		return "", err
+	}
+	if jobContext, ok := store.jobs[jobId]; ok && jobContext.Status != "RETIRED" {
+		jobContext.Status = "CANCELLED"
+		settleRetirement(&jobContext)
+		store.jobs[jobId] = jobContext
+		if err := SaveJob(jobId, jobContext, store.db); err != nil {
+			log.Printf("[-] Failed to save job %s: %v", jobId, err)
+		}
+	}
+	log.Printf("[+] Cancelled run %s", runId)
+
+	if run.WorkerId == "" {
+		return "[+] Run cancelled before it was dispatched", nil
+	}
+	if !sent {
+		return fmt.Sprintf("[*] Run cancelled, but worker %s could not be reached to stop it", run.WorkerId), nil
+	}
+	return fmt.Sprintf("[+] Run cancelled, worker %s is stopping it", run.WorkerId), nil
+}
+
+// stopRun ends a queued or running run with the given status: it is no longer
+// in flight, so it is skipped if still queued and the result its worker
+// reports is not counted, its lease and worker slot are freed, and the worker
+// holding it, if any, is asked to stop it. It reports whether that worker was
+// reached. Must be called with store.mu held.
+func stopRun(jobId string, run Run, status string) (bool, error) {
+	runId := run.RunId
+	finishRun(jobId, runId)
+	leases.release(runId)
+	workers.release(runId)
+	if err := queue.Remove(runId); err != nil {
+		log.Printf("[-] Failed to drop run %s from the queue: %v", runId, err)
+	}
+	if _, err := updateRun(runId, store.db, func(run *Run) {
+		run.Status = status
+		run.FinishedAt = time.Now()
+	}); err != nil {
+		return false, err
+	}
+	if run.WorkerId == "" {
+		return false, nil
+	}
+	return workers.send(run.WorkerId, &pb.ServerMessage{Message: &pb.ServerMessage_Cancel{Cancel: &pb.CancelRunRequest{RunId: runId}}}), nil
+}
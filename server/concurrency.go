@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// Concurrency policies, see Job.concurrency_policy
+const (
+	concurrencyAllow   = "allow"
+	concurrencyForbid  = "forbid"
+	concurrencyReplace = "replace"
+)
+
+func validateConcurrencyPolicy(policy string) error {
+	switch policy {
+	case "", concurrencyAllow, concurrencyForbid, concurrencyReplace:
+		return nil
+	}
+	return fmt.Errorf("unknown concurrency policy %q (want %s, %s or %s)", policy, concurrencyAllow, concurrencyForbid, concurrencyReplace)
+}
+
+// admitRun applies the job's concurrency policy before another run of it is
+// dispatched, and reports whether the dispatch may go ahead. Must be called
+// with store.mu held.
+func admitRun(jobContext *JobContext) bool {
+	jobId := jobContext.Job.Id
+	running := store.inflight[jobId]
+	if len(running) == 0 {
+		return true
+	}
+
+	switch jobContext.Job.ConcurrencyPolicy {
+	case concurrencyForbid:
+		for _, at := range jobContext.Owed {
+			recordMisfire(jobContext, at, "previous run still in progress")
+		}
+		log.Printf("[-] Job %s: skipped %d fire(s), previous run still in progress", jobId, len(jobContext.Owed))
+		jobContext.Owed = nil
+		return false
+	case concurrencyReplace:
+		// Superseded runs are stopped, and their results no longer count for the job
+		for id := range running {
+			log.Printf("[*] Job %s: run %s replaced by a new run", jobId, id)
+			run, err := LoadRun(id, store.db)
+			if err != nil {
+				log.Printf("[-] Failed to load replaced run %s: %v", id, err)
+				finishRun(jobId, id)
+				continue
+			}
+			if sent, err := stopRun(jobId, run, "REPLACED"); err != nil {
+				log.Printf("[-] Failed to save replaced run %s: %v", id, err)
+			} else if run.WorkerId != "" && !sent {
+				log.Printf("[-] Worker %s could not be reached to stop replaced run %s", run.WorkerId, id)
+			}
+		}
+	}
+	return true
+}
+
+// startRun records a dispatched run as in flight. Must be called with store.mu held.
+func startRun(jobId, runId string) {
+	if store.inflight[jobId] == nil {
+		store.inflight[jobId] = make(map[string]bool)
+	}
+	store.inflight[jobId][runId] = true
+}
+
+// finishRun removes a run from the in-flight set and reports whether it was
+// still tracked, i.e. not replaced. Must be called with store.mu held.
+func finishRun(jobId, runId string) bool {
+	running := store.inflight[jobId]
+	if !running[runId] {
+		return false
+	}
+	delete(running, runId)
+	if len(running) == 0 {
+		delete(store.inflight, jobId)
+	}
+	return true
+}
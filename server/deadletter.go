@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// DeadLetter is a run that failed for good, after any retries. Dead letters
+// are stored under "deadletter:<job id>:<seq>" until they are requeued, their
+// job is deleted or they are pruned past deadLetterHistory, and keep their own
+// copy of the last output so that pruning the run history does not lose it.
+type DeadLetter struct {
+	RunId       string
+	JobId       string
+	Image       string
+	Command     string
+	Reason      string
+	Output      string
+	Attempts    int
+	ExitCode    int
+	ScheduledAt time.Time
+	FailedAt    time.Time
+}
+
+// deadLetterHistory is how many dead letters are kept per job, older ones are
+// pruned
+const deadLetterHistory = 100
+
+var deadLetterPrefixAll = []byte("deadletter:")
+
+func deadLetterPrefix(jobId string) []byte {
+	return []byte("deadletter:" + jobId + ":")
+}
+
+func deadLetterKey(runId string) ([]byte, error) {
+	jobId, n, err := parseRunId(runId)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("deadletter:%s:%010d", jobId, n)), nil
+}
+
+// SaveDeadLetter stores a dead letter and prunes the oldest ones of its job
+// once it has more than deadLetterHistory of them
+func SaveDeadLetter(dl DeadLetter, db *badger.DB) error {
+	key, err := deadLetterKey(dl.RunId)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		jsonData, err := json.Marshal(dl)
+		if err != nil {
+			return err
+		}
+		if err := txn.Set(key, jsonData); err != nil {
+			return err
+		}
+
+		// Keys sort by run sequence, so the oldest dead letters come first
+		prefix := deadLetterPrefix(dl.JobId)
+		var keys [][]byte
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			keys = append(keys, it.Item().KeyCopy(nil))
+		}
+		it.Close()
+		for len(keys) > deadLetterHistory {
+			if err := txn.Delete(keys[0]); err != nil {
+				return err
+			}
+			keys = keys[1:]
+		}
+		return nil
+	})
+}
+
+func LoadDeadLetter(runId string, db *badger.DB) (DeadLetter, error) {
+	var dl DeadLetter
+	key, err := deadLetterKey(runId)
+	if err != nil {
+		return dl, err
+	}
+	err = db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		return item.Value(func(v []byte) error {
+			return json.Unmarshal(v, &dl)
+		})
+	})
+	return dl, err
+}
+
+func DeleteDeadLetter(runId string, db *badger.DB) error {
+	key, err := deadLetterKey(runId)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
+// ListDeadLetters returns the dead letters of a job, or of every job if jobId
+// is empty, most recently failed first. A limit of 0 returns all of them.
+func ListDeadLetters(jobId string, limit int, db *badger.DB) ([]DeadLetter, error) {
+	prefix := deadLetterPrefixAll
+	if jobId != "" {
+		prefix = deadLetterPrefix(jobId)
+	}
+	dls := []DeadLetter{}
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var dl DeadLetter
+			if err := it.Item().Value(func(v []byte) error { return json.Unmarshal(v, &dl) }); err != nil {
+				return err
+			}
+			dls = append(dls, dl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(dls, func(i, j int) bool { return dls[i].FailedAt.After(dls[j].FailedAt) })
+	if limit > 0 && len(dls) > limit {
+		dls = dls[:limit]
+	}
+	return dls, nil
+}
+
+// failureCause describes how an attempt of a run failed
+func failureCause(job *pb.Job, result *pb.JobResult, exitCode int) string {
+	switch {
+	case result.TimedOut:
+		return fmt.Sprintf("timed out after %s", job.Timeout)
+	case exitCode < 0 && result.Reason != "":
+		return result.Reason
+	case exitCode < 0:
+		return "the container could not be run"
+	case result.Reason != "":
+		return fmt.Sprintf("exit code %d, %s", exitCode, result.Reason)
+	}
+	return fmt.Sprintf("exit code %d", exitCode)
+}
+
+// deadLetterReason explains why a run that failed with cause is not retried,
+// given the attempt it failed on as counted by its retry policy
+func deadLetterReason(policy *pb.RetryPolicy, attempt int, cause string) string {
+	switch {
+	case policy == nil || policy.MaxAttempts <= 1:
+		return cause
+	case attempt >= int(policy.MaxAttempts):
+		return fmt.Sprintf("%s, after %d attempts", cause, attempt)
+	default:
+		return fmt.Sprintf("%s, which is not retried", cause)
+	}
+}
+
+// deadLetterRun parks a run that failed for good in the dead-letter
+// collection. Must be called with store.mu held, after the run record was
+// finished.
+func deadLetterRun(jobContext JobContext, runId, reason, output string) {
+	run, err := LoadRun(runId, store.db)
+	if err != nil {
+		log.Printf("[-] Failed to load run %s: %v", runId, err)
+		return
+	}
+	dl := DeadLetter{
+		RunId:       runId,
+		JobId:       run.JobId,
+		Image:       jobContext.Job.Image,
+		Command:     jobContext.Job.Command,
+		Reason:      reason,
+		Output:      output,
+		Attempts:    max(run.Attempt, 1),
+		ExitCode:    run.ExitCode,
+		ScheduledAt: run.ScheduledAt,
+		FailedAt:    run.FinishedAt,
+	}
+	if dl.FailedAt.IsZero() {
+		dl.FailedAt = time.Now()
+	}
+	if err := SaveDeadLetter(dl, store.db); err != nil {
+		log.Printf("[-] Failed to dead-letter run %s: %v", runId, err)
+		return
+	}
+	log.Printf("[-] Run %s dead-lettered: %s", runId, reason)
+}
+
+// requeueDeadLetter queues a dead-lettered run again with the current
+// definition of its job and a fresh retry budget. Must be called with
+// store.mu held.
+func requeueDeadLetter(runId string) error {
+	dl, err := LoadDeadLetter(runId, store.db)
+	if err != nil {
+		return err
+	}
+	jobContext, ok := store.jobs[dl.JobId]
+	if !ok {
+		return fmt.Errorf("job %s no longer exists", dl.JobId)
+	}
+	if store.inflight[dl.JobId][runId] {
+		return fmt.Errorf("run is already in flight")
+	}
+
+	job := proto.Clone(jobContext.Job).(*pb.Job)
+	job.RunId = runId
+	if err := queue.Enqueue(job, 0); err != nil {
+		return err
+	}
+	requeued := func(run *Run) {
+		nextAttempt(run)
+		run.RetryBase = run.Attempt
+		run.Status = "QUEUED"
+	}
+	_, err = updateRun(runId, store.db, requeued)
+	if err == badger.ErrKeyNotFound { // Pruned from the run history, start a new record
+		run := Run{RunId: runId, JobId: dl.JobId, ScheduledAt: dl.ScheduledAt,
+			Attempt: dl.Attempts, FinishedAt: dl.FailedAt, ExitCode: dl.ExitCode}
+		requeued(&run)
+		err = SaveRun(run, store.db)
+	}
+	if err != nil {
+		if err := queue.Remove(runId); err != nil {
+			log.Printf("[-] Failed to drop run %s from the queue: %v", runId, err)
+		}
+		return err
+	}
+	startRun(dl.JobId, runId)
+	if err := DeleteDeadLetter(runId, store.db); err != nil {
+		log.Printf("[-] Failed to remove dead letter of run %s: %v", runId, err)
+	}
+
+	if jobContext.Status != "RETIRED" {
+		jobContext.Status = "QUEUED"
+	}
+	jobContext.RunId = runId
+	jobContext.WorkerId = ""
+	jobContext.StartedAt = time.Time{}
+	store.jobs[dl.JobId] = jobContext
+	if err := SaveJob(dl.JobId, jobContext, store.db); err != nil {
+		log.Printf("[-] Failed to save job %s: %v", dl.JobId, err)
+	}
+	log.Printf("[+] Requeued dead-lettered run %s", runId)
+	return nil
+}
+
+func deadLetterToProto(dl DeadLetter) *pb.DeadLetter {
+	return &pb.DeadLetter{
+		RunId:       dl.RunId,
+		JobId:       dl.JobId,
+		Image:       dl.Image,
+		Command:     dl.Command,
+		Reason:      dl.Reason,
+		Output:      dl.Output,
+		Attempts:    int32(dl.Attempts),
+		ExitCode:    int32(dl.ExitCode),
+		ScheduledAt: unixOrZero(dl.ScheduledAt),
+		FailedAt:    unixOrZero(dl.FailedAt),
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+// TestSaveDeadLetterPrunes keeps deadLetterHistory dead letters per job and
+// drops the oldest ones
+func TestSaveDeadLetterPrunes(t *testing.T) {
+	db := openTestDB(t)
+	for n := 1; n <= deadLetterHistory+5; n++ {
+		if err := SaveDeadLetter(DeadLetter{RunId: runIdFor("job", n), JobId: "job"}, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SaveDeadLetter(DeadLetter{RunId: runIdFor("other", 1), JobId: "other"}, db); err != nil {
+		t.Fatal(err)
+	}
+
+	dls, err := ListDeadLetters("job", 0, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dls) != deadLetterHistory {
+		t.Fatalf("job has %d dead letters, want %d", len(dls), deadLetterHistory)
+	}
+	for n := 1; n <= 5; n++ {
+		if _, err := LoadDeadLetter(runIdFor("job", n), db); err == nil {
+			t.Errorf("dead letter %s was not pruned", runIdFor("job", n))
+		}
+	}
+	if _, err := LoadDeadLetter(runIdFor("job", 6), db); err != nil {
+		t.Errorf("dead letter %s was pruned: %v", runIdFor("job", 6), err)
+	}
+	if _, err := LoadDeadLetter(runIdFor("other", 1), db); err != nil {
+		t.Errorf("dead letter of another job was pruned: %v", err)
+	}
+}
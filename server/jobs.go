@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// scheduleFields are the Job fields that decide when a job fires; updating
+// any of them recomputes its next fire
+var scheduleFields = []string{"schedule", "timezone", "run_at", "run_in", "not_before", "not_after", "spread"}
+
+// DeleteJobRecords removes a job, its runs, their output and its dead letters
+// in one transaction
+func DeleteJobRecords(jobId string, db *badger.DB) error {
+	return db.Update(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefix := runPrefix(jobId)
+		keys := [][]byte{[]byte("job:" + jobId)}
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			n, err := strconv.Atoi(string(key[len(prefix):]))
+			if err != nil {
+				return fmt.Errorf("invalid run key %q", key)
+			}
+			keys = append(keys, key, []byte(outputKey(runIdFor(jobId, n))))
+		}
+		prefix = deadLetterPrefix(jobId)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			keys = append(keys, it.Item().KeyCopy(nil))
+		}
+		for _, key := range keys {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validateJobId checks the ID a job is submitted with. It is part of the
+// keys of the job's records, e.g. "run:<job id>:<seq>", so it must not
+// contain the ":" that separates them.
+func validateJobId(jobId string) error {
+	if jobId == "" {
+		return fmt.Errorf("job id is empty")
+	}
+	if strings.Contains(jobId, ":") {
+		return fmt.Errorf("job id %q must not contain \":\"", jobId)
+	}
+	return nil
+}
+
+// applyUpdate returns a copy of job with the fields named in mask taken from update
+func applyUpdate(job, update *pb.Job, mask []string) (*pb.Job, error) {
+	if len(mask) == 0 {
+		return nil, fmt.Errorf("update mask is empty")
+	}
+	merged := proto.Clone(job).(*pb.Job)
+	for _, path := range mask {
+		if path == "id" || path == "run_id" {
+			return nil, fmt.Errorf("field %q cannot be updated", path)
+		}
+		if err := copyField(update.ProtoReflect(), merged.ProtoReflect(), path); err != nil {
+			return nil, err
+		}
+	}
+	// A run_at only means something for one-offs
+	if merged.Schedule != oneOff {
+		merged.RunAt = ""
+	}
+	return merged, nil
+}
+
+// copyField copies the field at a dotted path such as "retry.max_attempts"
+// from src to dst, clearing it in dst if it is unset in src
+func copyField(src, dst protoreflect.Message, path string) error {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fd := dst.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return fmt.Errorf("field %q cannot be updated", path)
+		}
+		if i == len(names)-1 {
+			if src.Has(fd) {
+				dst.Set(fd, src.Get(fd))
+			} else {
+				dst.Clear(fd)
+			}
+			return nil
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return fmt.Errorf("field %q cannot be updated", path)
+		}
+		src, dst = src.Get(fd).Message(), dst.Mutable(fd).Message()
+	}
+	return nil
+}
+
+// updateJobContext validates an updated job and applies it to its context,
+// recomputing the next fire if the schedule changed. Must be called with
+// store.mu held.
+func updateJobContext(jobContext *JobContext, job *pb.Job, mask []string, now time.Time) error {
+	if _, err := jobLocation(job); err != nil {
+		return fmt.Errorf("invalid time zone %q: %v", job.Timezone, err)
+	}
+	if err := validateMisfirePolicy(job.MisfirePolicy, job.MisfireLimit); err != nil {
+		return err
+	}
+	if err := validateConcurrencyPolicy(job.ConcurrencyPolicy); err != nil {
+		return err
+	}
+	if err := validateRetryPolicy(job.Retry); err != nil {
+		return fmt.Errorf("invalid retry policy: %v", err)
+	}
+	if err := resolveRunAt(job, now); err != nil {
+		return err
+	}
+	if err := validateJitter(job); err != nil {
+		return err
+	}
+	if err := validateTimeout(job); err != nil {
+		return err
+	}
+	if err := validateResources(job); err != nil {
+		return err
+	}
+	if err := validatePlacement(job); err != nil {
+		return err
+	}
+	if err := validateLimits(job); err != nil {
+		return err
+	}
+	applyLimitDefaults(job)
+
+	next := jobContext.NextRun
+	if slices.ContainsFunc(mask, func(name string) bool { return slices.Contains(scheduleFields, name) }) {
+		var err error
+		if next, err = firstRun(job, now); err != nil {
+			return fmt.Errorf("invalid schedule %q: %v", job.Schedule, err)
+		}
+	} else if job.MaxRuns < 0 {
+		return fmt.Errorf("max_runs must not be negative, got %d", job.MaxRuns)
+	}
+
+	jobContext.Job = job
+	jobContext.NextRun = next
+	// New bounds may give a retired job, or one waiting for its last run to
+	// retire, more fires
+	if retireReason(*jobContext) == "" && (jobContext.Status == "RETIRED" || jobContext.NextRun.IsZero()) {
+		if jobContext.Status == "RETIRED" {
+			jobContext.Status = "QUEUED"
+			jobContext.RetiredReason = ""
+		}
+		if jobContext.NextRun.IsZero() {
+			jobContext.NextRun = nextRun(job, now)
+		}
+	}
+	settleRetirement(jobContext)
+	return nil
+}
+
+// resumeJob lets a paused job fire again. Fires of a recurring job that came
+// due while it was paused are dropped; a pending one-off runs right away.
+func resumeJob(jobContext *JobContext, now time.Time) {
+	jobContext.Paused = false
+	if jobContext.Job.Schedule != oneOff && !jobContext.NextRun.IsZero() && jobContext.NextRun.Before(now) {
+		jobContext.NextRun = nextRun(jobContext.Job, now)
+	}
+	settleRetirement(jobContext)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestApplyUpdate(t *testing.T) {
+	job := &pb.Job{
+		Id:       "job",
+		Image:    "alpine",
+		Command:  "echo hi",
+		Schedule: "60",
+		Labels:   map[string]string{"team": "data"},
+		Retry:    &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s"},
+	}
+	update := &pb.Job{
+		Image:    "alpine:3.20",
+		Schedule: oneOff,
+		RunAt:    "2030-01-01T00:00:00Z",
+		Labels:   map[string]string{"team": "web"},
+		Retry:    &pb.RetryPolicy{MaxAttempts: 5},
+		Limits:   &pb.ResourceLimits{MemoryMb: 256},
+	}
+
+	tests := []struct {
+		name string
+		mask []string
+		want *pb.Job
+	}{
+		{"one field", []string{"image"},
+			&pb.Job{Id: "job", Image: "alpine:3.20", Command: "echo hi", Schedule: "60", Labels: map[string]string{"team": "data"}, Retry: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s"}}},
+		{"unset field is cleared", []string{"command"},
+			&pb.Job{Id: "job", Image: "alpine", Schedule: "60", Labels: map[string]string{"team": "data"}, Retry: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s"}}},
+		{"map is replaced", []string{"labels"},
+			&pb.Job{Id: "job", Image: "alpine", Command: "echo hi", Schedule: "60", Labels: map[string]string{"team": "web"}, Retry: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s"}}},
+		{"nested field keeps its siblings", []string{"retry.max_attempts"},
+			&pb.Job{Id: "job", Image: "alpine", Command: "echo hi", Schedule: "60", Labels: map[string]string{"team": "data"}, Retry: &pb.RetryPolicy{MaxAttempts: 5, InitialBackoff: "5s"}}},
+		{"whole message is replaced", []string{"retry"},
+			&pb.Job{Id: "job", Image: "alpine", Command: "echo hi", Schedule: "60", Labels: map[string]string{"team": "data"}, Retry: &pb.RetryPolicy{MaxAttempts: 5}}},
+		{"nested field of an unset message", []string{"limits.memory_mb"},
+			&pb.Job{Id: "job", Image: "alpine", Command: "echo hi", Schedule: "60", Labels: map[string]string{"team": "data"}, Retry: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s"}, Limits: &pb.ResourceLimits{MemoryMb: 256}}},
+		{"run_at is kept for one-offs", []string{"schedule", "run_at"},
+			&pb.Job{Id: "job", Image: "alpine", Command: "echo hi", Schedule: oneOff, RunAt: "2030-01-01T00:00:00Z", Labels: map[string]string{"team": "data"}, Retry: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s"}}},
+		{"run_at is dropped for recurring jobs", []string{"run_at"},
+			&pb.Job{Id: "job", Image: "alpine", Command: "echo hi", Schedule: "60", Labels: map[string]string{"team": "data"}, Retry: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := proto.Clone(job)
+			got, err := applyUpdate(job, update, tt.mask)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("applyUpdate(%v) = %v, want %v", tt.mask, got, tt.want)
+			}
+			if !proto.Equal(job, original) {
+				t.Errorf("applyUpdate(%v) modified the job it was given", tt.mask)
+			}
+		})
+	}
+}
+
+func TestApplyUpdateErrors(t *testing.T) {
+	job := &pb.Job{Id: "job", Image: "alpine", Schedule: "60"}
+	for _, mask := range [][]string{
+		nil,
+		{"id"},
+		{"run_id"},
+		{"image", "id"},
+		{"no_such_field"},
+		{"retry.no_such_field"},
+		{"image.name"},
+		{"labels.team"},
+		{"limits.ulimits.soft"},
+	} {
+		if _, err := applyUpdate(job, &pb.Job{Id: "other"}, mask); err == nil {
+			t.Errorf("applyUpdate(%v) succeeded, want an error", mask)
+		}
+	}
+}
+
+func TestValidateJobId(t *testing.T) {
+	for id, valid := range map[string]bool{
+		"1471222889833185167": true,
+		"nightly-backup":      true,
+		"":                    false,
+		"a:b":                 false,
+	} {
+		if err := validateJobId(id); (err == nil) != valid {
+			t.Errorf("validateJobId(%q) = %v, want valid: %v", id, err, valid)
+		}
+	}
+}
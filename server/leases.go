@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+const (
+	leaseDuration      = 30 * time.Second // How long an ack or heartbeat keeps a run with its worker
+	leaseCheckInterval = 1 * time.Second
+)
+
+// lease is a run a worker acked, which it holds until expires unless it
+// keeps renewing it with heartbeats. Until the ack, the run is covered by the
+// visibility timeout of its queue item.
+type lease struct {
+	job      *pb.Job
+	workerId string
+	expires  time.Time
+}
+
+type leaseTable struct {
+	mu     sync.Mutex
+	leases map[string]*lease // runId -> lease
+}
+
+var leases = leaseTable{leases: make(map[string]*lease)}
+
+// grant leases an acked run to its worker
+func (l *leaseTable) grant(job *pb.Job, workerId string) *pb.Lease {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	expires := time.Now().Add(leaseDuration)
+	l.leases[job.RunId] = &lease{job: job, workerId: workerId, expires: expires}
+	return &pb.Lease{RunId: job.RunId, ExpiresAt: expires.Unix()}
+}
+
+// renew extends the leases a worker holds on the given runs and returns them.
+// Runs the worker does not hold, e.g. taken back already, are left out.
+func (l *leaseTable) renew(workerId string, runIds []string) []*pb.Lease {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	expires := time.Now().Add(leaseDuration)
+	granted := []*pb.Lease{}
+	for _, runId := range runIds {
+		held, ok := l.leases[runId]
+		if !ok || held.workerId != workerId {
+			continue
+		}
+		held.expires = expires
+		granted = append(granted, &pb.Lease{RunId: runId, ExpiresAt: expires.Unix()})
+	}
+	return granted
+}
+
+// holder returns the worker that holds the lease on a run, if any
+func (l *leaseTable) holder(runId string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	held, ok := l.leases[runId]
+	if !ok {
+		return "", false
+	}
+	return held.workerId, true
+}
+
+// release forgets the lease on a run that finished or was cancelled
+func (l *leaseTable) release(runId string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.leases, runId)
+}
+
+// expired removes and returns the leases that ran out
+func (l *leaseTable) expired(now time.Time) []*lease {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []*lease
+	for runId, held := range l.leases {
+		if now.After(held.expires) {
+			out = append(out, held)
+			delete(l.leases, runId)
+		}
+	}
+	return out
+}
+
+// holdsRun reports whether a worker still holds a run: it has the run's
+// lease or, while the run has none because it is not acked yet or was
+// stopped, it is the worker the run was last handed to. Must be called with
+// store.mu held.
+func holdsRun(workerId, runId string) bool {
+	if holder, ok := leases.holder(runId); ok {
+		return holder == workerId
+	}
+	run, err := LoadRun(runId, store.db)
+	return err == nil && run.WorkerId == workerId
+}
+
+// reclaimLeases takes back runs whose lease expired until ctx is cancelled
+func reclaimLeases(ctx context.Context) {
+	ticker := time.NewTicker(leaseCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, held := range leases.expired(now) {
+				reclaim(held)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reclaim takes a run back from a worker that let its lease expire and queues
+// it again. The worker is not sent a cancel, which could overtake the run if
+// it is sent to the same worker again; workers stop runs whose lease lapsed.
+func reclaim(held *lease) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	job := held.job
+	workers.release(job.RunId)
+	if !store.inflight[job.Id][job.RunId] {
+		return // Finished or cancelled meanwhile
+	}
+	log.Printf("[-] Lease of run %s on worker %s was not renewed, re-queuing", job.RunId, held.workerId)
+	requeue(job)
+}
+
+// requeue puts a run that was handed to a worker back in the queue. Must be
+// called with store.mu held.
+func requeue(job *pb.Job) {
+	if enqueueErr := queue.Enqueue(job, 0); enqueueErr != nil {
+		log.Printf("[-] Failed to re-queue run %s, it is lost: %v", job.RunId, enqueueErr)
+		finishRun(job.Id, job.RunId)
+		_, err := updateRun(job.RunId, store.db, func(run *Run) {
+			run.Status = "FAILED"
+			run.FinishedAt = time.Now()
+		})
+		if err != nil {
+			log.Printf("[-] Failed to save lost run %s: %v", job.RunId, err)
+		}
+		if jobContext, ok := store.jobs[job.Id]; ok {
+			deadLetterRun(jobContext, job.RunId, fmt.Sprintf("it could not be re-queued: %v", enqueueErr), "")
+			if settleRetirement(&jobContext) {
+				store.jobs[job.Id] = jobContext
+				if err := SaveJob(job.Id, jobContext, store.db); err != nil {
+					log.Printf("[-] Failed to save job %s: %v", job.Id, err)
+				}
+			}
+		}
+		return
+	}
+	_, err := updateRun(job.RunId, store.db, func(run *Run) {
+		run.Status = "QUEUED"
+		run.WorkerId = ""
+		run.StartedAt = time.Time{}
+	})
+	if err != nil {
+		log.Printf("[-] Failed to save re-queued run %s: %v", job.RunId, err)
+	}
+	setRequeued(job.Id, job.RunId)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+func TestLeaseTable(t *testing.T) {
+	l := &leaseTable{leases: make(map[string]*lease)}
+	before := time.Now()
+	granted := l.grant(&pb.Job{Id: "job", RunId: "job-1"}, "a")
+	l.grant(&pb.Job{Id: "job", RunId: "job-2"}, "b")
+	if granted.RunId != "job-1" || granted.ExpiresAt < before.Add(leaseDuration).Unix() {
+		t.Errorf("grant() = %v, want job-1 until %v", granted, before.Add(leaseDuration))
+	}
+	if holder, ok := l.holder("job-1"); !ok || holder != "a" {
+		t.Errorf("holder(job-1) = %q, %v, want a", holder, ok)
+	}
+	if _, ok := l.holder("job-3"); ok {
+		t.Error("holder() found a run that was never leased")
+	}
+
+	// A worker only renews the runs it holds
+	renewed := l.renew("a", []string{"job-1", "job-2", "job-3"})
+	if len(renewed) != 1 || renewed[0].RunId != "job-1" {
+		t.Errorf("renew() = %v, want job-1 only", renewed)
+	}
+	if holder, _ := l.holder("job-2"); holder != "b" {
+		t.Errorf("renew() by another worker took job-2 from b, holder is %q", holder)
+	}
+
+	if expired := l.expired(time.Now()); len(expired) != 0 {
+		t.Errorf("expired() = %d leases before they ran out", len(expired))
+	}
+	l.release("job-2")
+	if _, ok := l.holder("job-2"); ok {
+		t.Error("released lease is still held")
+	}
+	expired := l.expired(time.Now().Add(leaseDuration + time.Second))
+	if len(expired) != 1 || expired[0].job.RunId != "job-1" || expired[0].workerId != "a" {
+		t.Fatalf("expired() = %v, want the lease of a on job-1", expired)
+	}
+	if _, ok := l.holder("job-1"); ok {
+		t.Error("expired lease is still held")
+	}
+	if renewed := l.renew("a", []string{"job-1"}); len(renewed) != 0 {
+		t.Errorf("renew() = %v after the lease expired, want none", renewed)
+	}
+}
+
+// TestLeaseRenewExtends keeps a renewed lease from expiring at its first
+// deadline
+func TestLeaseRenewExtends(t *testing.T) {
+	l := &leaseTable{leases: make(map[string]*lease)}
+	l.grant(&pb.Job{Id: "job", RunId: "job-1"}, "a")
+	first := l.leases["job-1"].expires
+	time.Sleep(10 * time.Millisecond)
+	l.renew("a", []string{"job-1"})
+	if expired := l.expired(first.Add(time.Millisecond)); len(expired) != 0 {
+		t.Error("renewed lease expired at its first deadline")
+	}
+}
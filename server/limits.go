@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// limitPolicy holds the container limits the server puts on jobs that leave
+// them unset, and the largest limits a job may ask for. 0 = none.
+type limitPolicy struct {
+	defaults pb.ResourceLimits
+	maximums pb.ResourceLimits
+}
+
+var limitPolicies limitPolicy
+
+// registerLimitFlags adds the server flags that configure limitPolicies
+func registerLimitFlags() {
+	flag.Int64Var(&limitPolicies.defaults.MemoryMb, "default-memory-mb", 0, "Memory limit of jobs that set none, in MB")
+	flag.Int64Var(&limitPolicies.maximums.MemoryMb, "max-memory-mb", 0, "Largest memory limit a job may set, in MB")
+	flag.Float64Var(&limitPolicies.defaults.Cpus, "default-cpus", 0, "CPU limit of jobs that set none")
+	flag.Float64Var(&limitPolicies.maximums.Cpus, "max-cpus", 0, "Largest CPU limit a job may set")
+	flag.Int64Var(&limitPolicies.defaults.Pids, "default-pids", 0, "PID limit of jobs that set none")
+	flag.Int64Var(&limitPolicies.maximums.Pids, "max-pids", 0, "Largest PID limit a job may set")
+}
+
+// applyLimitDefaults fills in the limits a job leaves unset, once they are
+// validated. With a maximum but no default, the maximum is the default, as no
+// limit would exceed it. A default below the job's request is raised to the
+// request, up to the maximum, so the server's own default never rejects a job.
+func applyLimitDefaults(job *pb.Job) {
+	if job.Limits == nil {
+		job.Limits = &pb.ResourceLimits{}
+	}
+	l, defaults, maximums := job.Limits, &limitPolicies.defaults, &limitPolicies.maximums
+	if l.MemoryMb == 0 {
+		l.MemoryMb = defaultLimit(defaults.MemoryMb, maximums.MemoryMb, int64(job.MemoryMb))
+	}
+	if l.Cpus == 0 {
+		l.Cpus = defaultLimit(defaults.Cpus, maximums.Cpus, job.Cpus)
+	}
+	if l.Pids == 0 {
+		l.Pids = orMaximum(defaults.Pids, maximums.Pids)
+	}
+	if proto.Equal(l, &pb.ResourceLimits{}) {
+		job.Limits = nil
+	}
+}
+
+// orMaximum returns def if it is set, otherwise maximum
+func orMaximum[T int64 | float64](def, maximum T) T {
+	if def != 0 {
+		return def
+	}
+	return maximum
+}
+
+// defaultLimit returns the limit of a job that sets none: the default or
+// maximum, raised to the job's request but not past the maximum. It is 0, no
+// limit, if the server has neither.
+func defaultLimit[T int64 | float64](def, maximum, request T) T {
+	limit := orMaximum(def, maximum)
+	if limit == 0 {
+		return 0
+	}
+	limit = max(limit, request)
+	if maximum > 0 {
+		limit = min(limit, maximum)
+	}
+	return limit
+}
+
+// validateLimits checks the container limits a job sets, before defaults are
+// filled in, against each other, its resource requests and the server's
+// maximums
+func validateLimits(job *pb.Job) error {
+	l := job.Limits
+	if l == nil {
+		return nil
+	}
+	maximums := &limitPolicies.maximums
+	if l.MemoryMb < 0 {
+		return fmt.Errorf("memory limit must not be negative, got %d MB", l.MemoryMb)
+	}
+	if maximums.MemoryMb > 0 && l.MemoryMb > maximums.MemoryMb {
+		return fmt.Errorf("memory limit %d MB is above the maximum of %d MB", l.MemoryMb, maximums.MemoryMb)
+	}
+	if l.MemoryMb > 0 && int64(job.MemoryMb) > l.MemoryMb {
+		return fmt.Errorf("memory limit %d MB is below the memory request of %d MB", l.MemoryMb, job.MemoryMb)
+	}
+	switch {
+	case l.MemorySwapMb == 0 || l.MemorySwapMb == -1:
+	case l.MemorySwapMb < 0:
+		return fmt.Errorf("memory+swap limit must be -1 (unlimited) or positive, got %d MB", l.MemorySwapMb)
+	case l.MemoryMb == 0:
+		return fmt.Errorf("memory+swap limit needs a memory limit")
+	case l.MemorySwapMb < l.MemoryMb:
+		return fmt.Errorf("memory+swap limit %d MB is below the memory limit of %d MB", l.MemorySwapMb, l.MemoryMb)
+	}
+	if l.Cpus < 0 || math.IsNaN(l.Cpus) || math.IsInf(l.Cpus, 0) {
+		return fmt.Errorf("CPU limit must be a non-negative number, got %g", l.Cpus)
+	}
+	if maximums.Cpus > 0 && l.Cpus > maximums.Cpus {
+		return fmt.Errorf("CPU limit %g is above the maximum of %g", l.Cpus, maximums.Cpus)
+	}
+	if l.Cpus > 0 && job.Cpus > l.Cpus {
+		return fmt.Errorf("CPU limit %g is below the CPU request of %g", l.Cpus, job.Cpus)
+	}
+	if l.CpuShares < 0 || l.CpuShares == 1 {
+		return fmt.Errorf("CPU shares must be at least 2, got %d", l.CpuShares)
+	}
+	if l.Pids < 0 {
+		return fmt.Errorf("PID limit must not be negative, got %d", l.Pids)
+	}
+	if maximums.Pids > 0 && l.Pids > maximums.Pids {
+		return fmt.Errorf("PID limit %d is above the maximum of %d", l.Pids, maximums.Pids)
+	}
+	seen := map[string]bool{}
+	for _, u := range l.Ulimits {
+		if u.Name == "" {
+			return fmt.Errorf("ulimit without a name")
+		}
+		if seen[u.Name] {
+			return fmt.Errorf("ulimit %s is set twice", u.Name)
+		}
+		seen[u.Name] = true
+		if u.Soft < -1 || u.Hard < -1 || u.Hard != -1 && (u.Soft == -1 || u.Soft > u.Hard) {
+			return fmt.Errorf("ulimit %s: soft limit %d must not exceed hard limit %d", u.Name, u.Soft, u.Hard)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// setLimitPolicies configures the server's limit defaults and maximums for
+// the rest of a test
+func setLimitPolicies(t *testing.T, defaults, maximums *pb.ResourceLimits) {
+	t.Helper()
+	proto.Reset(&limitPolicies.defaults)
+	proto.Reset(&limitPolicies.maximums)
+	proto.Merge(&limitPolicies.defaults, defaults)
+	proto.Merge(&limitPolicies.maximums, maximums)
+	t.Cleanup(func() {
+		proto.Reset(&limitPolicies.defaults)
+		proto.Reset(&limitPolicies.maximums)
+	})
+}
+
+func TestApplyLimitDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults *pb.ResourceLimits
+		maximums *pb.ResourceLimits
+		limits   *pb.ResourceLimits
+		request  *pb.Job // Requests of the job, if any
+		want     *pb.ResourceLimits
+	}{
+		{"no policy, no limits", &pb.ResourceLimits{}, &pb.ResourceLimits{}, nil, nil, nil},
+		{"no policy", &pb.ResourceLimits{}, &pb.ResourceLimits{}, &pb.ResourceLimits{MemoryMb: 256}, nil, &pb.ResourceLimits{MemoryMb: 256}},
+		{"defaults fill in unset limits", &pb.ResourceLimits{MemoryMb: 512, Cpus: 1, Pids: 100}, &pb.ResourceLimits{}, nil, nil,
+			&pb.ResourceLimits{MemoryMb: 512, Cpus: 1, Pids: 100}},
+		{"set limits are kept", &pb.ResourceLimits{MemoryMb: 512, Cpus: 1, Pids: 100}, &pb.ResourceLimits{}, &pb.ResourceLimits{MemoryMb: 128, CpuShares: 512}, nil,
+			&pb.ResourceLimits{MemoryMb: 128, Cpus: 1, Pids: 100, CpuShares: 512}},
+		{"maximum is the default without one", &pb.ResourceLimits{}, &pb.ResourceLimits{MemoryMb: 2048, Cpus: 4, Pids: 500}, nil, nil,
+			&pb.ResourceLimits{MemoryMb: 2048, Cpus: 4, Pids: 500}},
+		{"default before maximum", &pb.ResourceLimits{MemoryMb: 512}, &pb.ResourceLimits{MemoryMb: 2048}, nil, nil,
+			&pb.ResourceLimits{MemoryMb: 512}},
+		{"default raised to the request", &pb.ResourceLimits{MemoryMb: 512, Cpus: 1}, &pb.ResourceLimits{}, nil, &pb.Job{MemoryMb: 1024, Cpus: 2},
+			&pb.ResourceLimits{MemoryMb: 1024, Cpus: 2}},
+		{"raised default capped at the maximum", &pb.ResourceLimits{MemoryMb: 512, Cpus: 1}, &pb.ResourceLimits{MemoryMb: 768, Cpus: 1.5}, nil, &pb.Job{MemoryMb: 1024, Cpus: 2},
+			&pb.ResourceLimits{MemoryMb: 768, Cpus: 1.5}},
+		{"default above the request", &pb.ResourceLimits{MemoryMb: 512}, &pb.ResourceLimits{}, nil, &pb.Job{MemoryMb: 256},
+			&pb.ResourceLimits{MemoryMb: 512}},
+		{"request alone sets no limit", &pb.ResourceLimits{}, &pb.ResourceLimits{}, nil, &pb.Job{MemoryMb: 256}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimitPolicies(t, tt.defaults, tt.maximums)
+			job := &pb.Job{Limits: tt.limits}
+			if tt.request != nil {
+				job.MemoryMb, job.Cpus = tt.request.MemoryMb, tt.request.Cpus
+			}
+			if err := validateLimits(job); err != nil {
+				t.Fatalf("validateLimits() = %v before defaults", err)
+			}
+			applyLimitDefaults(job)
+			if !proto.Equal(job.Limits, tt.want) {
+				t.Errorf("limits = %v, want %v", job.Limits, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		maximums *pb.ResourceLimits
+		job      *pb.Job
+		wantErr  bool
+	}{
+		{"no limits", &pb.ResourceLimits{}, &pb.Job{}, false},
+		{"all limits", &pb.ResourceLimits{}, &pb.Job{Cpus: 0.5, MemoryMb: 256, Limits: &pb.ResourceLimits{
+			MemoryMb: 512, MemorySwapMb: 1024, Cpus: 1, CpuShares: 512, Pids: 100,
+			Ulimits: []*pb.Ulimit{{Name: "nofile", Soft: 1024, Hard: 4096}, {Name: "core", Soft: -1, Hard: -1}},
+		}}, false},
+		{"at the maximums", &pb.ResourceLimits{MemoryMb: 512, Cpus: 2, Pids: 100}, &pb.Job{Limits: &pb.ResourceLimits{MemoryMb: 512, Cpus: 2, Pids: 100}}, false},
+		{"negative memory", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{MemoryMb: -1}}, true},
+		{"memory above the maximum", &pb.ResourceLimits{MemoryMb: 512}, &pb.Job{Limits: &pb.ResourceLimits{MemoryMb: 1024}}, true},
+		{"memory below the request", &pb.ResourceLimits{}, &pb.Job{MemoryMb: 1024, Limits: &pb.ResourceLimits{MemoryMb: 512}}, true},
+		{"unlimited swap", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{MemoryMb: 512, MemorySwapMb: -1}}, false},
+		{"negative swap", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{MemoryMb: 512, MemorySwapMb: -2}}, true},
+		{"swap without memory", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{MemorySwapMb: 1024}}, true},
+		{"swap below memory", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{MemoryMb: 512, MemorySwapMb: 256}}, true},
+		{"negative cpus", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{Cpus: -1}}, true},
+		{"cpus above the maximum", &pb.ResourceLimits{Cpus: 2}, &pb.Job{Limits: &pb.ResourceLimits{Cpus: 2.5}}, true},
+		{"cpus below the request", &pb.ResourceLimits{}, &pb.Job{Cpus: 2, Limits: &pb.ResourceLimits{Cpus: 1}}, true},
+		{"one cpu share", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{CpuShares: 1}}, true},
+		{"negative pids", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{Pids: -1}}, true},
+		{"pids above the maximum", &pb.ResourceLimits{Pids: 100}, &pb.Job{Limits: &pb.ResourceLimits{Pids: 101}}, true},
+		{"ulimit without a name", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{Ulimits: []*pb.Ulimit{{Soft: 1, Hard: 1}}}}, true},
+		{"ulimit set twice", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{Ulimits: []*pb.Ulimit{{Name: "nofile", Soft: 1, Hard: 1}, {Name: "nofile", Soft: 2, Hard: 2}}}}, true},
+		{"ulimit soft above hard", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{Ulimits: []*pb.Ulimit{{Name: "nofile", Soft: 4096, Hard: 1024}}}}, true},
+		{"ulimit unlimited soft under a hard limit", &pb.ResourceLimits{}, &pb.Job{Limits: &pb.ResourceLimits{Ulimits: []*pb.Ulimit{{Name: "nofile", Soft: -1, Hard: 1024}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimitPolicies(t, &pb.ResourceLimits{}, tt.maximums)
+			if err := validateLimits(tt.job); (err != nil) != tt.wantErr {
+				t.Errorf("validateLimits() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// pageCursor is the position after the last job of a page, encoded in the page token
+type pageCursor struct {
+	Value string // Sort value of the last job
+	Id    string
+}
+
+func encodePageToken(c pageCursor) string {
+	jsonData, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(jsonData)
+}
+
+func decodePageToken(token string) (pageCursor, error) {
+	var c pageCursor
+	if token == "" {
+		return c, nil
+	}
+	jsonData, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("invalid page token")
+	}
+	if err := json.Unmarshal(jsonData, &c); err != nil {
+		return c, fmt.Errorf("invalid page token")
+	}
+	return c, nil
+}
+
+// sortValue returns the field a job is ordered by, as a string that sorts correctly
+func sortValue(jobContext JobContext, orderBy string) string {
+	switch orderBy {
+	case "next_run":
+		return fmt.Sprintf("%020d", unixOrZero(jobContext.NextRun))
+	case "created":
+		return fmt.Sprintf("%020d", unixOrZero(jobContext.CreatedAt))
+	case "status":
+		return jobContext.Status
+	case "image":
+		return jobContext.Job.Image
+	}
+	return jobContext.Job.Id
+}
+
+// jobFilter holds the ListJobs filters
+type jobFilter struct {
+	status string
+	image  string
+	labels map[string]string
+}
+
+func newJobFilter(req *pb.ListJobsRequest) (jobFilter, error) {
+	f := jobFilter{status: req.Status, image: req.Image, labels: map[string]string{}}
+	for _, selector := range req.Labels {
+		key, value, ok := strings.Cut(selector, "=")
+		if !ok || key == "" {
+			return f, fmt.Errorf("invalid label selector %q, want key=value", selector)
+		}
+		f.labels[key] = value
+	}
+	return f, nil
+}
+
+func (f jobFilter) matches(jobContext JobContext) bool {
+	// PAUSED is not a status of its own, a paused job keeps the outcome of its latest run
+	if f.status != "" && !strings.EqualFold(f.status, jobContext.Status) && !(jobContext.Paused && strings.EqualFold(f.status, "PAUSED")) {
+		return false
+	}
+	if image := jobContext.Job.Image; f.image != "" && image != f.image && !strings.HasPrefix(image, f.image+":") {
+		return false
+	}
+	for key, value := range f.labels {
+		if jobContext.Job.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ListJobs scans the jobs in badger and returns one page of those matching
+// the request, along with the token of the next page
+func ListJobs(req *pb.ListJobsRequest, db *badger.DB) ([]JobContext, string, error) {
+	filter, err := newJobFilter(req)
+	if err != nil {
+		return nil, "", err
+	}
+	cursor, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	orderBy := req.OrderBy
+	switch orderBy {
+	case "":
+		orderBy = "id"
+	case "id", "next_run", "created", "status", "image":
+	default:
+		return nil, "", fmt.Errorf("cannot order by %q", req.OrderBy)
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	// after reports whether a job comes after the cursor in the requested order
+	after := func(value, id string) bool {
+		if req.PageToken == "" {
+			return true
+		}
+		c := strings.Compare(value, cursor.Value)
+		if c == 0 {
+			c = strings.Compare(id, cursor.Id)
+		}
+		if req.Descending {
+			return c < 0
+		}
+		return c > 0
+	}
+
+	// Ascending by id is the key order, so the scan can start at the cursor
+	// and stop as soon as the page is full
+	streaming := orderBy == "id" && !req.Descending
+
+	jobs := []JobContext{}
+	err = db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte("job:")
+		start := prefix
+		if streaming && cursor.Id != "" {
+			start = []byte("job:" + cursor.Id)
+		}
+		for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
+			if streaming && len(jobs) > pageSize {
+				break
+			}
+			if streaming && cursor.Id != "" && bytes.Equal(it.Item().Key(), start) {
+				continue
+			}
+			var jobContext JobContext
+			if err := it.Item().Value(func(v []byte) error { return json.Unmarshal(v, &jobContext) }); err != nil {
+				return err
+			}
+			if jobContext.Job == nil || !filter.matches(jobContext) {
+				continue
+			}
+			if !streaming && !after(sortValue(jobContext, orderBy), jobContext.Job.Id) {
+				continue
+			}
+			jobs = append(jobs, jobContext)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !streaming {
+		sort.Slice(jobs, func(i, j int) bool {
+			a, b := sortValue(jobs[i], orderBy), sortValue(jobs[j], orderBy)
+			if a == b {
+				a, b = jobs[i].Job.Id, jobs[j].Job.Id
+			}
+			if req.Descending {
+				return a > b
+			}
+			return a < b
+		})
+	}
+
+	// One extra job was collected to know whether there is a next page
+	nextToken := ""
+	if len(jobs) > pageSize {
+		jobs = jobs[:pageSize]
+		last := jobs[len(jobs)-1]
+		nextToken = encodePageToken(pageCursor{Value: sortValue(last, orderBy), Id: last.Job.Id})
+	}
+	return jobs, nextToken, nil
+}
+
+func jobSummary(jobContext JobContext) *pb.JobSummary {
+	return &pb.JobSummary{
+		JobId:     jobContext.Job.Id,
+		Image:     jobContext.Job.Image,
+		Command:   jobContext.Job.Command,
+		Schedule:  jobContext.Job.Schedule,
+		Status:    jobContext.Status,
+		NextRun:   unixOrZero(jobContext.NextRun),
+		CreatedAt: unixOrZero(jobContext.CreatedAt),
+		Runs:      int32(jobContext.Runs),
+		Labels:    jobContext.Job.Labels,
+		Paused:    jobContext.Paused,
+	}
+}
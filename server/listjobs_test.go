@@ -0,0 +1,158 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// openTestDB opens an in-memory badger, closed when the test ends
+func openTestDB(t *testing.T) *badger.DB {
+	t.Helper()
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func seedListJobs(t *testing.T) *badger.DB {
+	t.Helper()
+	db := openTestDB(t)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	jobs := []struct {
+		id, image, status string
+		labels            map[string]string
+		paused            bool
+		next              int // Hours after base, 0 = no next run
+	}{
+		{"a", "alpine", "COMPLETED", map[string]string{"team": "data"}, false, 3},
+		{"b", "alpine:3.20", "FAILED", map[string]string{"team": "web"}, false, 1},
+		{"c", "busybox", "COMPLETED", nil, true, 3},
+		{"d", "busybox:latest", "QUEUED", map[string]string{"team": "data"}, false, 2},
+		{"e", "alpine", "FAILED", map[string]string{"team": "data", "tier": "1"}, false, 1},
+		{"f", "alpinelinux", "QUEUED", nil, false, 0},
+		{"g", "alpine", "RETIRED", nil, false, 0},
+	}
+	for i, job := range jobs {
+		jobContext := JobContext{
+			Job:       &pb.Job{Id: job.id, Image: job.image, Labels: job.labels},
+			Status:    job.status,
+			Paused:    job.paused,
+			CreatedAt: base.Add(-time.Duration(i) * time.Minute), // Created in reverse id order
+		}
+		if job.next > 0 {
+			jobContext.NextRun = base.Add(time.Duration(job.next) * time.Hour)
+		}
+		if err := SaveJob(job.id, jobContext, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func jobIds(jobs []JobContext) []string {
+	ids := []string{}
+	for _, jobContext := range jobs {
+		ids = append(ids, jobContext.Job.Id)
+	}
+	return ids
+}
+
+func TestListJobsFilter(t *testing.T) {
+	db := seedListJobs(t)
+	tests := []struct {
+		name string
+		req  *pb.ListJobsRequest
+		want []string
+	}{
+		{"all", &pb.ListJobsRequest{}, []string{"a", "b", "c", "d", "e", "f", "g"}},
+		{"status ignores case", &pb.ListJobsRequest{Status: "failed"}, []string{"b", "e"}},
+		{"paused", &pb.ListJobsRequest{Status: "PAUSED"}, []string{"c"}},
+		{"paused job keeps its status", &pb.ListJobsRequest{Status: "COMPLETED"}, []string{"a", "c"}},
+		{"image with any tag", &pb.ListJobsRequest{Image: "alpine"}, []string{"a", "b", "e", "g"}},
+		{"image with tag", &pb.ListJobsRequest{Image: "busybox:latest"}, []string{"d"}},
+		{"label", &pb.ListJobsRequest{Labels: []string{"team=data"}}, []string{"a", "d", "e"}},
+		{"all labels must match", &pb.ListJobsRequest{Labels: []string{"team=data", "tier=1"}}, []string{"e"}},
+		{"filters combine", &pb.ListJobsRequest{Status: "QUEUED", Labels: []string{"team=data"}}, []string{"d"}},
+		{"no match", &pb.ListJobsRequest{Image: "nginx"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, next, err := ListJobs(tt.req, db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := jobIds(jobs); !slices.Equal(got, tt.want) {
+				t.Errorf("ListJobs() = %v, want %v", got, tt.want)
+			}
+			if next != "" {
+				t.Errorf("ListJobs() returned next page token %q for a single page", next)
+			}
+		})
+	}
+}
+
+func TestListJobsErrors(t *testing.T) {
+	db := seedListJobs(t)
+	for name, req := range map[string]*pb.ListJobsRequest{
+		"label without value": {Labels: []string{"team"}},
+		"label without key":   {Labels: []string{"=data"}},
+		"unknown order":       {OrderBy: "command"},
+		"bad page token":      {PageToken: "not a token"},
+		"garbled page token":  {PageToken: "bm90IGpzb24"},
+	} {
+		if _, _, err := ListJobs(req, db); err == nil {
+			t.Errorf("%s: ListJobs() succeeded, want an error", name)
+		}
+	}
+}
+
+// TestListJobsPages walks every order page by page and checks the pages add
+// up to the full listing, without gaps or repeats
+func TestListJobsPages(t *testing.T) {
+	db := seedListJobs(t)
+	tests := []struct {
+		orderBy    string
+		descending bool
+		want       []string
+	}{
+		{"", false, []string{"a", "b", "c", "d", "e", "f", "g"}},
+		{"id", true, []string{"g", "f", "e", "d", "c", "b", "a"}},
+		{"next_run", false, []string{"f", "g", "b", "e", "d", "a", "c"}},
+		{"next_run", true, []string{"c", "a", "d", "e", "b", "g", "f"}},
+		{"created", false, []string{"g", "f", "e", "d", "c", "b", "a"}},
+		{"status", true, []string{"g", "f", "d", "e", "b", "c", "a"}},
+		{"image", false, []string{"a", "e", "g", "b", "f", "c", "d"}},
+	}
+	for _, tt := range tests {
+		for _, pageSize := range []int32{1, 2, 3, 7} {
+			got := []string{}
+			token := ""
+			for pages := 0; ; pages++ {
+				if pages > len(tt.want) {
+					t.Fatalf("order %q desc %v size %d: no last page", tt.orderBy, tt.descending, pageSize)
+				}
+				jobs, next, err := ListJobs(&pb.ListJobsRequest{OrderBy: tt.orderBy, Descending: tt.descending, PageSize: pageSize, PageToken: token}, db)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(jobs) > int(pageSize) {
+					t.Fatalf("order %q desc %v: page of %d jobs, want at most %d", tt.orderBy, tt.descending, len(jobs), pageSize)
+				}
+				got = append(got, jobIds(jobs)...)
+				if next == "" {
+					break
+				}
+				token = next
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order %q desc %v size %d: pages = %v, want %v", tt.orderBy, tt.descending, pageSize, got, tt.want)
+			}
+		}
+	}
+}
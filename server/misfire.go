@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Misfire policies, see Job.misfire_policy
+const (
+	misfireSkip     = "skip"
+	misfireFireOnce = "fire_once"
+	misfireFireAll  = "fire_all"
+)
+
+const (
+	defaultMisfireLimit = 10
+	misfireHistory      = 50              // Skipped fires kept per job
+	maxMissedScan       = 1000            // Missed occurrences enumerated on startup
+	misfireRetryDelay   = 1 * time.Second // How soon owed fires are retried after they could not be queued
+)
+
+// Misfire is a scheduled fire that was never dispatched
+type Misfire struct {
+	ScheduledAt time.Time
+	Reason      string
+}
+
+func validateMisfirePolicy(policy string, limit int32) error {
+	switch policy {
+	case "", misfireSkip, misfireFireOnce, misfireFireAll:
+	default:
+		return fmt.Errorf("unknown misfire policy %q (want %s, %s or %s)", policy, misfireSkip, misfireFireOnce, misfireFireAll)
+	}
+	if limit < 0 {
+		return fmt.Errorf("misfire limit must not be negative, got %d", limit)
+	}
+	return nil
+}
+
+// settleMisfires applies the job's misfire policy to the fires it is owed but
+// could not dispatch, keeping the most recent ones it is allowed to replay and
+// recording the rest as skipped
+func settleMisfires(jobContext *JobContext, reason string) {
+	keep := len(jobContext.Owed)
+	switch jobContext.Job.MisfirePolicy {
+	case misfireSkip:
+		keep = 0
+	case misfireFireAll:
+		limit := int(jobContext.Job.MisfireLimit)
+		if limit == 0 {
+			limit = defaultMisfireLimit
+		}
+		keep = min(keep, limit)
+	default:
+		keep = min(keep, 1)
+	}
+	// A one-off job has nothing after its single fire, so it is never skipped
+	if jobContext.Job.Schedule == oneOff {
+		keep = max(keep, min(len(jobContext.Owed), 1))
+	}
+
+	skipped := jobContext.Owed[:len(jobContext.Owed)-keep]
+	for _, at := range skipped {
+		recordMisfire(jobContext, at, reason)
+	}
+	if len(skipped) > 0 {
+		log.Printf("[-] Job %s: skipped %d fire(s) (%s)", jobContext.Job.Id, len(skipped), reason)
+	}
+	jobContext.Owed = append([]time.Time(nil), jobContext.Owed[len(skipped):]...)
+}
+
+func recordMisfire(jobContext *JobContext, at time.Time, reason string) {
+	jobContext.MisfireCount++
+	jobContext.Misfires = append(jobContext.Misfires, Misfire{ScheduledAt: at, Reason: reason})
+	if len(jobContext.Misfires) > misfireHistory {
+		jobContext.Misfires = jobContext.Misfires[len(jobContext.Misfires)-misfireHistory:]
+	}
+}
+
+// catchUp collects the fires a job missed while the server was down and
+// settles them according to its misfire policy. It reports whether the job
+// was changed.
+func catchUp(jobContext *JobContext, now time.Time) bool {
+	missed := 0
+	for !jobContext.NextRun.IsZero() && jobContext.NextRun.Before(now) && missed < maxMissedScan {
+		jobContext.Owed = append(jobContext.Owed, jobContext.NextRun)
+		jobContext.NextRun = nextRun(jobContext.Job, jobContext.NextRun)
+		missed++
+	}
+	if missed == 0 && len(jobContext.Owed) == 0 {
+		return false
+	}
+	if !jobContext.NextRun.IsZero() && jobContext.NextRun.Before(now) {
+		jobContext.NextRun = nextRun(jobContext.Job, now) // Gave up enumerating, resume from now
+	}
+	settleMisfires(jobContext, "server was down")
+	return true
+}
+
+// nextFire returns when the scheduler should next look at the job: its next
+// regular (jittered) fire, or sooner if it still owes fires that could not be
+// enqueued
+func nextFire(jobContext JobContext, now time.Time) time.Time {
+	due := dueAt(jobContext)
+	if len(jobContext.Owed) > 0 {
+		retry := now.Add(misfireRetryDelay)
+		if due.IsZero() || retry.Before(due) {
+			return retry
+		}
+	}
+	return due
+}
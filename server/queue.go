@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// visibilityTimeout is how long a run handed to a worker stays hidden from
+// other workers; unless the worker acks it by then it is handed out again
+const visibilityTimeout = 10 * time.Second
+
+// queueItem is a run waiting for a worker. Items are stored under
+// "queue:<enqueue time>:<run id>" so that a scan returns them in FIFO order,
+// and stay there until the worker they were handed to acks them.
+type queueItem struct {
+	Job        *pb.Job
+	EnqueuedAt time.Time
+	VisibleAt  time.Time // Hidden from Dequeue until then, while a worker is expected to ack it or a retry backs off
+	WorkerId   string    // Worker it was last handed to, empty while backing off
+	Deliveries int
+}
+
+// queueEntry indexes an item in memory, so that Dequeue and Stats only read
+// the items that can be handed out
+type queueEntry struct {
+	runId      string
+	key        []byte
+	enqueuedAt time.Time
+	visibleAt  time.Time
+	workerId   string
+}
+
+// DurableQueue is the dispatch queue, kept in badger so that it survives
+// restarts and has no fixed capacity
+type DurableQueue struct {
+	mu      sync.Mutex
+	db      *badger.DB
+	entries map[string]*queueEntry // runId -> its entry
+	order   []*queueEntry          // Sorted by key, i.e. in FIFO order
+	ready   chan struct{}          // Closed and replaced whenever an item is added or made visible
+}
+
+var queue = newDurableQueue()
+
+func newDurableQueue() *DurableQueue {
+	return &DurableQueue{entries: make(map[string]*queueEntry), ready: make(chan struct{})}
+}
+
+var queuePrefix = []byte("queue:")
+
+func queueKey(runId string, at time.Time) []byte {
+	return []byte(fmt.Sprintf("queue:%020d:%s", at.UnixNano(), runId))
+}
+
+// Load indexes the items left in badger by a previous run of the server
+func (q *DurableQueue) Load(db *badger.DB) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.db = db
+	return db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(queuePrefix); it.ValidForPrefix(queuePrefix); it.Next() {
+			var item queueItem
+			if err := it.Item().Value(func(v []byte) error { return json.Unmarshal(v, &item) }); err != nil {
+				return err
+			}
+			q.index(item, it.Item().KeyCopy(nil))
+		}
+		return nil
+	})
+}
+
+// index adds the entry of an item. Must be called with q.mu held.
+func (q *DurableQueue) index(item queueItem, key []byte) {
+	entry := &queueEntry{runId: item.Job.RunId, key: key, enqueuedAt: item.EnqueuedAt, visibleAt: item.VisibleAt, workerId: item.WorkerId}
+	i, _ := slices.BinarySearchFunc(q.order, key, func(e *queueEntry, key []byte) int { return bytes.Compare(e.key, key) })
+	q.order = slices.Insert(q.order, i, entry)
+	q.entries[entry.runId] = entry
+}
+
+// forget drops the entry of an item. Must be called with q.mu held.
+func (q *DurableQueue) forget(runId string) {
+	delete(q.entries, runId)
+	q.order = slices.DeleteFunc(q.order, func(e *queueEntry) bool { return e.runId == runId })
+}
+
+// notify wakes every worker waiting for an item. Must be called with q.mu held.
+func (q *DurableQueue) notify() {
+	close(q.ready)
+	q.ready = make(chan struct{})
+}
+
+// Wait returns a channel closed once an item is added or made visible. Take
+// it before calling Dequeue so no wake-up is missed.
+func (q *DurableQueue) Wait() <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.ready
+}
+
+// Has reports whether a run is in the queue
+func (q *DurableQueue) Has(runId string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, ok := q.entries[runId]
+	return ok
+}
+
+// Enqueue adds a run to the back of the queue, to be handed out after delay
+func (q *DurableQueue) Enqueue(job *pb.Job, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.entries[job.RunId]; ok {
+		return fmt.Errorf("run %s is already queued", job.RunId)
+	}
+	now := time.Now()
+	key := queueKey(job.RunId, now)
+	item := queueItem{Job: job, EnqueuedAt: now, VisibleAt: now.Add(delay)}
+	jsonData, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	err = q.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, jsonData)
+	})
+	if err != nil {
+		return err
+	}
+	q.index(item, key)
+	q.notify()
+	return nil
+}
+
+// Dequeue hands the oldest visible run that claim accepts to a worker and
+// hides it for the visibility timeout. Runs claim turns down stay queued for
+// other workers. If no run is handed out it returns nil, along with when the
+// next hidden one becomes visible again (zero if there is none). If it fails
+// after claim accepted a run, that run is returned with the error.
+func (q *DurableQueue) Dequeue(workerId string, claim func(job *pb.Job) bool) (*pb.Job, time.Time, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Hidden items are skipped on their index entry, only visible ones are
+	// read for claim to look at
+	now := time.Now()
+	var job *pb.Job
+	var next time.Time
+	var claimed *queueEntry
+	err := q.db.Update(func(txn *badger.Txn) error {
+		for _, entry := range q.order {
+			if entry.visibleAt.After(now) {
+				if next.IsZero() || entry.visibleAt.Before(next) {
+					next = entry.visibleAt
+				}
+				continue
+			}
+			stored, err := txn.Get(entry.key)
+			if err != nil {
+				return err
+			}
+			var item queueItem
+			if err := stored.Value(func(v []byte) error { return json.Unmarshal(v, &item) }); err != nil {
+				return err
+			}
+			if !claim(item.Job) {
+				continue
+			}
+			job = item.Job
+			item.VisibleAt = now.Add(visibilityTimeout)
+			item.WorkerId = workerId
+			item.Deliveries++
+			jsonData, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			claimed = entry
+			return txn.Set(entry.key, jsonData)
+		}
+		return nil
+	})
+	if err == nil && claimed != nil {
+		claimed.visibleAt = now.Add(visibilityTimeout)
+		claimed.workerId = workerId
+	}
+	if err != nil || job != nil {
+		return job, time.Time{}, err
+	}
+	return nil, next, nil
+}
+
+// update applies fn to the item of a run and saves it. Must be called with
+// q.mu held.
+func (q *DurableQueue) update(runId string, fn func(item *queueItem) error) error {
+	entry, ok := q.entries[runId]
+	if !ok {
+		return fmt.Errorf("run %s is not queued", runId)
+	}
+	var item queueItem
+	err := q.db.Update(func(txn *badger.Txn) error {
+		stored, err := txn.Get(entry.key)
+		if err != nil {
+			return err
+		}
+		if err := stored.Value(func(v []byte) error { return json.Unmarshal(v, &item) }); err != nil {
+			return err
+		}
+		if err := fn(&item); err != nil {
+			return err
+		}
+		if item.Job == nil { // Dropped by fn
+			return txn.Delete(entry.key)
+		}
+		jsonData, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		return txn.Set(entry.key, jsonData)
+	})
+	if err != nil {
+		return err
+	}
+	if item.Job == nil {
+		q.forget(runId)
+	} else {
+		entry.visibleAt = item.VisibleAt
+		entry.workerId = item.WorkerId
+	}
+	return nil
+}
+
+// Ack removes a run once the worker it was handed to confirms it has it,
+// and returns the run
+func (q *DurableQueue) Ack(runId, workerId string) (*pb.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var job *pb.Job
+	err := q.update(runId, func(item *queueItem) error {
+		if item.WorkerId != workerId {
+			return fmt.Errorf("run %s is no longer handed to worker %s", runId, workerId)
+		}
+		job, item.Job = item.Job, nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Release makes a run handed to a worker visible again right away, e.g.
+// because sending it failed
+func (q *DurableQueue) Release(runId string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	err := q.update(runId, func(item *queueItem) error {
+		item.VisibleAt = time.Now()
+		item.WorkerId = ""
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	q.notify()
+	return nil
+}
+
+// Remove drops a run from the queue, e.g. because it was cancelled
+func (q *DurableQueue) Remove(runId string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entry, ok := q.entries[runId]
+	if !ok {
+		return nil
+	}
+	err := q.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(entry.key)
+	})
+	if err != nil {
+		return err
+	}
+	q.forget(runId)
+	return nil
+}
+
+// Stats returns the number of runs in the queue, how many of them are handed
+// to a worker awaiting its ack or waiting out a retry backoff, and when the
+// oldest was enqueued
+func (q *DurableQueue) Stats() (depth, delivering, delayed int, oldest time.Time, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	for _, entry := range q.order {
+		depth++
+		switch {
+		case !entry.visibleAt.After(now):
+		case entry.workerId != "":
+			delivering++
+		default:
+			delayed++
+		}
+		if oldest.IsZero() || entry.enqueuedAt.Before(oldest) {
+			oldest = entry.enqueuedAt
+		}
+	}
+	return depth, delivering, delayed, oldest, nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// newTestQueue returns a queue kept in an in-memory badger
+func newTestQueue(t *testing.T) (*DurableQueue, *badger.DB) {
+	t.Helper()
+	db := openTestDB(t)
+	q := newDurableQueue()
+	if err := q.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	return q, db
+}
+
+func enqueueRuns(t *testing.T, q *DurableQueue, runIds ...string) {
+	t.Helper()
+	for _, runId := range runIds {
+		if err := q.Enqueue(&pb.Job{Id: "job", RunId: runId}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func claimAll(*pb.Job) bool { return true }
+
+// dequeueRun hands the next run to a worker and returns its id, empty if there
+// was none
+func dequeueRun(t *testing.T, q *DurableQueue, workerId string) string {
+	t.Helper()
+	job, _, err := q.Dequeue(workerId, claimAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job == nil {
+		return ""
+	}
+	return job.RunId
+}
+
+// TestQueueOrder hands runs out in the order they were enqueued, also after
+// the queue is loaded again from badger
+func TestQueueOrder(t *testing.T) {
+	q, db := newTestQueue(t)
+	enqueueRuns(t, q, "job-1", "job-2", "job-3")
+
+	loaded := newDurableQueue()
+	if err := loaded.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	for name, q := range map[string]*DurableQueue{"queue": q, "loaded": loaded} {
+		got := []string{}
+		for runId := dequeueRun(t, q, "a"); runId != ""; runId = dequeueRun(t, q, "a") {
+			got = append(got, runId)
+		}
+		if want := []string{"job-1", "job-2", "job-3"}; !slices.Equal(got, want) {
+			t.Errorf("%s handed out %v, want %v", name, got, want)
+		}
+	}
+}
+
+// TestQueueClaim leaves runs claim turns down for other workers
+func TestQueueClaim(t *testing.T) {
+	q, _ := newTestQueue(t)
+	enqueueRuns(t, q, "job-1", "job-2")
+	job, _, err := q.Dequeue("a", func(job *pb.Job) bool { return job.RunId != "job-1" })
+	if err != nil || job == nil || job.RunId != "job-2" {
+		t.Fatalf("Dequeue() = %v, %v, want job-2", job, err)
+	}
+	if runId := dequeueRun(t, q, "b"); runId != "job-1" {
+		t.Errorf("turned down run went to %q, want job-1", runId)
+	}
+}
+
+// TestQueueVisibility hides a handed out run until the visibility timeout
+// and reports when it becomes visible again
+func TestQueueVisibility(t *testing.T) {
+	q, _ := newTestQueue(t)
+	enqueueRuns(t, q, "job-1")
+	before := time.Now()
+	if runId := dequeueRun(t, q, "a"); runId != "job-1" {
+		t.Fatalf("Dequeue() = %q, want job-1", runId)
+	}
+	after := time.Now()
+
+	job, next, err := q.Dequeue("b", claimAll)
+	if err != nil || job != nil {
+		t.Fatalf("Dequeue() = %v, %v while the run is hidden, want nothing", job, err)
+	}
+	if next.Before(before.Add(visibilityTimeout)) || next.After(after.Add(visibilityTimeout)) {
+		t.Errorf("next visible at %v, want within [%v, %v]", next, before.Add(visibilityTimeout), after.Add(visibilityTimeout))
+	}
+	depth, delivering, delayed, _, _ := q.Stats()
+	if depth != 1 || delivering != 1 || delayed != 0 {
+		t.Errorf("Stats() = %d queued, %d delivering, %d delayed, want 1, 1, 0", depth, delivering, delayed)
+	}
+}
+
+// TestQueueDelayed does not hand out a delayed run before its delay, and
+// hands out runs enqueued after it meanwhile
+func TestQueueDelayed(t *testing.T) {
+	q, _ := newTestQueue(t)
+	before := time.Now()
+	if err := q.Enqueue(&pb.Job{Id: "job", RunId: "job-1"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	job, next, err := q.Dequeue("a", claimAll)
+	if err != nil || job != nil {
+		t.Fatalf("Dequeue() = %v, %v, want the delayed run held back", job, err)
+	}
+	if next.Before(before.Add(time.Hour)) || next.After(time.Now().Add(time.Hour)) {
+		t.Errorf("next visible at %v, want an hour from now", next)
+	}
+	depth, delivering, delayed, _, _ := q.Stats()
+	if depth != 1 || delivering != 0 || delayed != 1 {
+		t.Errorf("Stats() = %d queued, %d delivering, %d delayed, want 1, 0, 1", depth, delivering, delayed)
+	}
+
+	enqueueRuns(t, q, "job-2")
+	if runId := dequeueRun(t, q, "a"); runId != "job-2" {
+		t.Errorf("Dequeue() = %q, want job-2 ahead of the delayed run", runId)
+	}
+}
+
+func TestQueueAck(t *testing.T) {
+	q, _ := newTestQueue(t)
+	enqueueRuns(t, q, "job-1")
+	dequeueRun(t, q, "a")
+
+	if _, err := q.Ack("job-1", "b"); err == nil {
+		t.Error("Ack() from a worker the run was not handed to succeeded")
+	}
+	if !q.Has("job-1") {
+		t.Fatal("rejected Ack() removed the run")
+	}
+	if job, err := q.Ack("job-1", "a"); err != nil || job.RunId != "job-1" {
+		t.Fatalf("Ack() = %v, %v, want job-1", job, err)
+	}
+	if q.Has("job-1") {
+		t.Error("acked run is still queued")
+	}
+	if _, err := q.Ack("job-1", "a"); err == nil {
+		t.Error("second Ack() succeeded")
+	}
+}
+
+// TestQueueRelease makes a handed out run visible again right away, for
+// another worker to take
+func TestQueueRelease(t *testing.T) {
+	q, _ := newTestQueue(t)
+	enqueueRuns(t, q, "job-1")
+	dequeueRun(t, q, "a")
+
+	freed := q.Wait()
+	if err := q.Release("job-1"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-freed:
+	default:
+		t.Error("Release() did not wake waiting workers")
+	}
+	if runId := dequeueRun(t, q, "b"); runId != "job-1" {
+		t.Fatalf("Dequeue() = %q after Release(), want job-1", runId)
+	}
+	if _, err := q.Ack("job-1", "a"); err == nil {
+		t.Error("Ack() from the worker the run was released from succeeded")
+	}
+	if _, err := q.Ack("job-1", "b"); err != nil {
+		t.Errorf("Ack() = %v, want the run", err)
+	}
+	if err := q.Release("job-1"); err == nil {
+		t.Error("Release() of an acked run succeeded")
+	}
+}
+
+func TestQueueRemove(t *testing.T) {
+	q, db := newTestQueue(t)
+	enqueueRuns(t, q, "job-1", "job-2")
+	dequeueRun(t, q, "a")
+
+	for _, runId := range []string{"job-1", "job-2", "job-3"} {
+		if err := q.Remove(runId); err != nil {
+			t.Fatalf("Remove(%s) = %v", runId, err)
+		}
+	}
+	if q.Has("job-1") || q.Has("job-2") {
+		t.Error("removed runs are still queued")
+	}
+	if runId := dequeueRun(t, q, "b"); runId != "" {
+		t.Errorf("Dequeue() = %q, want nothing", runId)
+	}
+
+	loaded := newDurableQueue()
+	if err := loaded.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	if depth, _, _, _, _ := loaded.Stats(); depth != 0 {
+		t.Errorf("%d removed runs came back on Load()", depth)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// workerRetention is how long a worker stays listed after it disconnected
+const workerRetention = 7 * 24 * time.Hour
+
+// WorkerRecord is what the server knows about a worker that connected to it.
+// Records are stored under "worker:<id>", so that workers that went away are
+// still listed, until workerRetention after they disconnected.
+type WorkerRecord struct {
+	WorkerId       string
+	Labels         map[string]string
+	Version        string
+	Slots          int
+	Cpus           float64
+	MemoryMb       int64
+	ConnectedAt    time.Time // Start of its current or latest session
+	LastHeartbeat  time.Time
+	DisconnectedAt time.Time // Zero while connected
+	Cordoned       bool      // Gets no new runs until uncordoned
+}
+
+// workerRegistry holds a record of every worker that connected recently
+type workerRegistry struct {
+	mu      sync.Mutex
+	records map[string]*WorkerRecord
+	db      *badger.DB
+}
+
+var registry = workerRegistry{records: make(map[string]*WorkerRecord)}
+
+var workerPrefix = []byte("worker:")
+
+func SaveWorker(record WorkerRecord, db *badger.DB) error {
+	return db.Update(func(txn *badger.Txn) error {
+		jsonData, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return txn.Set([]byte("worker:"+record.WorkerId), jsonData)
+	})
+}
+
+func DeleteWorker(workerId string, db *badger.DB) error {
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte("worker:" + workerId))
+	})
+}
+
+// Load restores the worker records. Workers that were connected when the
+// server stopped lost their session with it, so they are recorded as
+// disconnected as of their last heartbeat.
+func (r *workerRegistry) Load(db *badger.DB) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.db = db
+	records := []WorkerRecord{}
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(workerPrefix); it.ValidForPrefix(workerPrefix); it.Next() {
+			var record WorkerRecord
+			if err := it.Item().Value(func(v []byte) error { return json.Unmarshal(v, &record) }); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.DisconnectedAt.IsZero() {
+			record.DisconnectedAt = record.LastHeartbeat
+			if err := SaveWorker(record, db); err != nil {
+				return err
+			}
+		}
+		r.records[record.WorkerId] = &record
+	}
+	r.prune(time.Now())
+	return nil
+}
+
+// connected records a new session of a worker and returns when it started,
+// which identifies the session, and whether the worker is cordoned
+func (r *workerRegistry) connected(hello *pb.WorkerHello) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	cordoned := false
+	if old, ok := r.records[hello.WorkerId]; ok {
+		cordoned = old.Cordoned // A worker stays cordoned across reconnects
+	}
+	record := &WorkerRecord{
+		WorkerId:      hello.WorkerId,
+		Labels:        hello.Labels,
+		Version:       hello.Version,
+		Slots:         helloSlots(hello),
+		Cpus:          hello.Cpus,
+		MemoryMb:      int64(hello.MemoryMb),
+		ConnectedAt:   now,
+		LastHeartbeat: now,
+		Cordoned:      cordoned,
+	}
+	r.records[hello.WorkerId] = record
+	r.save(record)
+	return now, cordoned
+}
+
+// heartbeat records that a worker is alive
+func (r *workerRegistry) heartbeat(workerId string, session time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if record, ok := r.records[workerId]; ok && record.ConnectedAt.Equal(session) {
+		record.LastHeartbeat = time.Now()
+		r.save(record)
+	}
+}
+
+// disconnected records the end of a session, unless the worker already
+// started a new one
+func (r *workerRegistry) disconnected(workerId string, session time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if record, ok := r.records[workerId]; ok && record.ConnectedAt.Equal(session) {
+		record.DisconnectedAt = time.Now()
+		r.save(record)
+	}
+}
+
+// list returns the records of the workers, sorted by ID
+func (r *workerRegistry) list() []WorkerRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune(time.Now())
+	records := make([]WorkerRecord, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].WorkerId < records[j].WorkerId })
+	return records
+}
+
+func (r *workerRegistry) get(workerId string) (WorkerRecord, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[workerId]
+	if !ok {
+		return WorkerRecord{}, false
+	}
+	return *record, true
+}
+
+// cordon marks a worker as cordoned or not, and returns its record
+func (r *workerRegistry) cordon(workerId string, cordoned bool) (WorkerRecord, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[workerId]
+	if !ok {
+		return WorkerRecord{}, false
+	}
+	if record.Cordoned != cordoned {
+		record.Cordoned = cordoned
+		r.save(record)
+	}
+	return *record, true
+}
+
+// save persists a record. Must be called with r.mu held.
+func (r *workerRegistry) save(record *WorkerRecord) {
+	if r.db == nil {
+		return
+	}
+	if err := SaveWorker(*record, r.db); err != nil {
+		log.Printf("[-] Failed to save worker %s: %v", record.WorkerId, err)
+	}
+}
+
+// prune forgets workers that disconnected more than workerRetention ago.
+// Must be called with r.mu held.
+func (r *workerRegistry) prune(now time.Time) {
+	for workerId, record := range r.records {
+		if record.DisconnectedAt.IsZero() || now.Sub(record.DisconnectedAt) < workerRetention {
+			continue
+		}
+		delete(r.records, workerId)
+		if r.db == nil {
+			continue
+		}
+		if err := DeleteWorker(workerId, r.db); err != nil {
+			log.Printf("[-] Failed to delete worker %s: %v", workerId, err)
+		}
+	}
+}
+
+// workerStatus tells whether a worker is connected and heartbeating
+func workerStatus(record WorkerRecord, now time.Time) string {
+	switch {
+	case !record.DisconnectedAt.IsZero():
+		return "DISCONNECTED"
+	case now.Sub(record.LastHeartbeat) > leaseDuration:
+		return "UNRESPONSIVE" // The leases on its runs lapsed
+	}
+	return "CONNECTED"
+}
+
+// matchesWorkerStatus reports whether a worker status passes a status filter
+func matchesWorkerStatus(status, filter string) bool {
+	return filter == "" || strings.EqualFold(status, filter)
+}
+
+// workerToProto combines the record of a worker with the runs it holds
+func workerToProto(record WorkerRecord, now time.Time) *pb.Worker {
+	worker := &pb.Worker{
+		WorkerId:       record.WorkerId,
+		Status:         workerStatus(record, now),
+		Labels:         record.Labels,
+		Version:        record.Version,
+		Slots:          int32(record.Slots),
+		Cpus:           record.Cpus,
+		MemoryMb:       record.MemoryMb,
+		ConnectedAt:    unixOrZero(record.ConnectedAt),
+		LastHeartbeat:  unixOrZero(record.LastHeartbeat),
+		DisconnectedAt: unixOrZero(record.DisconnectedAt),
+		Cordoned:       record.Cordoned,
+	}
+	if record.DisconnectedAt.IsZero() {
+		worker.RunIds, worker.CpusUsed, worker.MemoryUsedMb = workers.holding(record.WorkerId)
+		worker.Draining = workers.isDraining(record.WorkerId)
+	}
+	return worker
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Defaults of the RetryPolicy fields left unset
+const (
+	defaultInitialBackoff    = 10 * time.Second
+	defaultBackoffMultiplier = 2.0
+	defaultMaxBackoff        = time.Hour
+)
+
+// RunAttempt is an earlier attempt of a run, which failed and was retried
+type RunAttempt struct {
+	Attempt    int
+	WorkerId   string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	ExitCode   int
+	OOMKilled  bool
+	Reason     string
+}
+
+// parseBackoff parses a backoff duration, using def when it is empty
+func parseBackoff(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative, got %s", s)
+	}
+	return d, nil
+}
+
+func validateRetryPolicy(policy *pb.RetryPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must not be negative, got %d", policy.MaxAttempts)
+	}
+	if _, err := parseBackoff(policy.InitialBackoff, defaultInitialBackoff); err != nil {
+		return fmt.Errorf("invalid initial backoff %q: %v", policy.InitialBackoff, err)
+	}
+	if _, err := parseBackoff(policy.MaxBackoff, defaultMaxBackoff); err != nil {
+		return fmt.Errorf("invalid max backoff %q: %v", policy.MaxBackoff, err)
+	}
+	if policy.Multiplier != 0 && policy.Multiplier < 1 {
+		return fmt.Errorf("backoff multiplier must be at least 1, got %g", policy.Multiplier)
+	}
+	return nil
+}
+
+// retryBackoff returns how long to wait before the attempt that follows the
+// failed-th failed attempt of a run
+func retryBackoff(policy *pb.RetryPolicy, failed int) time.Duration {
+	initial, _ := parseBackoff(policy.InitialBackoff, defaultInitialBackoff)
+	maxBackoff, _ := parseBackoff(policy.MaxBackoff, defaultMaxBackoff)
+	multiplier := policy.Multiplier
+	if multiplier == 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+	backoff := float64(initial) * math.Pow(multiplier, float64(failed-1))
+	if backoff > float64(maxBackoff) {
+		return maxBackoff
+	}
+	return time.Duration(backoff)
+}
+
+// retryAttempt returns the attempt a run is on as counted by its retry policy
+func retryAttempt(run Run) int {
+	return max(run.Attempt, 1) - max(run.RetryBase, 1) + 1
+}
+
+// retryDelay reports whether the failed attempt-th attempt of a run is
+// retried under the policy, and after how long
+func retryDelay(policy *pb.RetryPolicy, attempt, exitCode int) (time.Duration, bool) {
+	if policy == nil || attempt >= int(policy.MaxAttempts) {
+		return 0, false
+	}
+	if len(policy.RetryOnExitCodes) > 0 && !slices.Contains(policy.RetryOnExitCodes, int32(exitCode)) {
+		return 0, false
+	}
+	return retryBackoff(policy, attempt), true
+}
+
+// retryRun records the failed attempt of a run and queues its next attempt
+// after delay. The run stays in flight meanwhile. Must be called with
+// store.mu held.
+func retryRun(jobContext *JobContext, result *pb.JobResult, exitCode int, delay time.Duration) error {
+	runId := result.RunId
+	if err := SaveOutput(runId, result.Output, store.db); err != nil {
+		return err
+	}
+	run, err := updateRun(runId, store.db, func(run *Run) {
+		applyResult(run, result, exitCode)
+		run.FinishedAt = time.Now()
+		nextAttempt(run)
+		run.Status = "RETRYING"
+		run.OutputRef = outputKey(runId)
+	})
+	if err != nil {
+		return err
+	}
+
+	job := proto.Clone(jobContext.Job).(*pb.Job)
+	job.RunId = runId
+	if err := queue.Enqueue(job, delay); err != nil {
+		return err
+	}
+	if jobContext.Status != "RETIRED" {
+		jobContext.Status = "RETRYING"
+	}
+	if jobContext.RunId == runId {
+		jobContext.WorkerId = ""
+		jobContext.StartedAt = time.Time{}
+	}
+	log.Printf("[*] Run %s failed (%s), attempt %d in %s", runId, failureCause(jobContext.Job, result, exitCode), run.Attempt, delay)
+	return nil
+}
+
+// nextAttempt moves the latest attempt of a run into its earlier attempts and
+// resets the run for the next one
+func nextAttempt(run *Run) {
+	run.Attempts = append(run.Attempts, RunAttempt{
+		Attempt:    max(run.Attempt, 1),
+		WorkerId:   run.WorkerId,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		ExitCode:   run.ExitCode,
+		OOMKilled:  run.OOMKilled,
+		Reason:     run.Reason,
+	})
+	run.Attempt = max(run.Attempt, 1) + 1
+	run.WorkerId = ""
+	run.StartedAt = time.Time{}
+	run.FinishedAt = time.Time{}
+	run.ExitCode = -1
+	run.OOMKilled = false
+	run.Reason = ""
+	run.ContainerStartedAt = time.Time{}
+	run.ContainerFinishedAt = time.Time{}
+}
+
+func attemptsToProto(attempts []RunAttempt) []*pb.RunAttempt {
+	out := make([]*pb.RunAttempt, 0, len(attempts))
+	for _, a := range attempts {
+		out = append(out, &pb.RunAttempt{
+			Attempt:    int32(a.Attempt),
+			WorkerId:   a.WorkerId,
+			StartedAt:  unixOrZero(a.StartedAt),
+			FinishedAt: unixOrZero(a.FinishedAt),
+			ExitCode:   int32(a.ExitCode),
+			OomKilled:  a.OOMKilled,
+			Reason:     a.Reason,
+		})
+	}
+	return out
+}
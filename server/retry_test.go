@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+func TestRetryDelay(t *testing.T) {
+	defaults := &pb.RetryPolicy{MaxAttempts: 5}
+	custom := &pb.RetryPolicy{MaxAttempts: 10, InitialBackoff: "1s", Multiplier: 3, MaxBackoff: "20s"}
+	onExit := &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "5s", RetryOnExitCodes: []int32{2, -1}}
+	huge := &pb.RetryPolicy{MaxAttempts: 5000, InitialBackoff: "1s"}
+
+	tests := []struct {
+		name      string
+		policy    *pb.RetryPolicy
+		attempt   int
+		exitCode  int
+		wantDelay time.Duration
+		wantRetry bool
+	}{
+		{"no policy", nil, 1, 1, 0, false},
+		{"no retries", &pb.RetryPolicy{}, 1, 1, 0, false},
+		{"defaults, first retry", defaults, 1, 1, 10 * time.Second, true},
+		{"defaults, doubles", defaults, 3, 1, 40 * time.Second, true},
+		{"last attempt is not retried", defaults, 5, 1, 0, false},
+		{"custom multiplier", custom, 2, 1, 3 * time.Second, true},
+		{"capped at max backoff", custom, 4, 1, 20 * time.Second, true},
+		{"stays capped", custom, 9, 1, 20 * time.Second, true},
+		{"no overflow after many attempts", huge, 4000, 1, time.Hour, true},
+		{"listed exit code", onExit, 1, 2, 5 * time.Second, true},
+		{"could not be run", onExit, 2, -1, 10 * time.Second, true},
+		{"unlisted exit code", onExit, 1, 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := retryDelay(tt.policy, tt.attempt, tt.exitCode)
+			if delay != tt.wantDelay || retry != tt.wantRetry {
+				t.Errorf("retryDelay(attempt %d, exit %d) = %v, %v, want %v, %v", tt.attempt, tt.exitCode, delay, retry, tt.wantDelay, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestValidateRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *pb.RetryPolicy
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"defaults", &pb.RetryPolicy{MaxAttempts: 3}, false},
+		{"custom", &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: "500ms", Multiplier: 1.5, MaxBackoff: "10m"}, false},
+		{"negative attempts", &pb.RetryPolicy{MaxAttempts: -1}, true},
+		{"bad backoff", &pb.RetryPolicy{InitialBackoff: "soon"}, true},
+		{"negative max backoff", &pb.RetryPolicy{MaxBackoff: "-1s"}, true},
+		{"shrinking backoff", &pb.RetryPolicy{Multiplier: 0.5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRetryPolicy(tt.policy); (err != nil) != tt.wantErr {
+				t.Errorf("validateRetryPolicy() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
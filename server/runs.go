@@ -0,0 +1,369 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// runHistory is how many runs are kept per job, older ones are pruned
+const runHistory = 100
+
+// Run is one execution of a job. Runs are stored under "run:<job id>:<seq>"
+// so that the runs of a job sort in dispatch order, and their output is
+// stored separately under OutputRef so listing runs stays cheap.
+type Run struct {
+	RunId       string
+	JobId       string
+	WorkerId    string
+	Status      string // "QUEUED", "RUNNING", "RETRYING", "COMPLETED", "FAILED", "TIMED_OUT", "REPLACED", "CANCELLED"
+	ScheduledAt time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	ExitCode    int // -1 until the worker reports one
+	OutputRef   string
+	Attempt     int          // Current attempt, from 1
+	Attempts    []RunAttempt // Earlier attempts that failed and were retried
+	RetryBase   int          // Attempt the retry policy counts from, moved on when a dead-lettered run is requeued
+	OOMKilled   bool
+	Reason      string // Why the container ended, as reported by the worker
+
+	// When the container started and ended, as reported by the worker;
+	// StartedAt and FinishedAt are when the server dispatched the run and
+	// got its result
+	ContainerStartedAt  time.Time
+	ContainerFinishedAt time.Time
+}
+
+// runIdFor names the n-th run of a job
+func runIdFor(jobId string, n int) string {
+	return fmt.Sprintf("%s-%d", jobId, n)
+}
+
+// parseRunId splits a run ID back into its job ID and sequence number
+func parseRunId(runId string) (string, int, error) {
+	i := strings.LastIndex(runId, "-")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid run id %q", runId)
+	}
+	n, err := strconv.Atoi(runId[i+1:])
+	if err != nil || n <= 0 {
+		return "", 0, fmt.Errorf("invalid run id %q", runId)
+	}
+	return runId[:i], n, nil
+}
+
+func runPrefix(jobId string) []byte {
+	return []byte("run:" + jobId + ":")
+}
+
+func runKeyOf(jobId string, n int) []byte {
+	return []byte(fmt.Sprintf("run:%s:%010d", jobId, n))
+}
+
+func runKey(runId string) ([]byte, error) {
+	jobId, n, err := parseRunId(runId)
+	if err != nil {
+		return nil, err
+	}
+	return runKeyOf(jobId, n), nil
+}
+
+func outputKey(runId string) string {
+	return "output:" + runId
+}
+
+func SaveRun(run Run, db *badger.DB) error {
+	key, err := runKey(run.RunId)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		jsonData, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return txn.Set(key, jsonData)
+	})
+}
+
+func LoadRun(runId string, db *badger.DB) (Run, error) {
+	var run Run
+	key, err := runKey(runId)
+	if err != nil {
+		return run, err
+	}
+	err = db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		return item.Value(func(v []byte) error {
+			return json.Unmarshal(v, &run)
+		})
+	})
+	return run, err
+}
+
+// updateRun loads a run, applies fn and saves it back in one transaction
+func updateRun(runId string, db *badger.DB, fn func(run *Run)) (Run, error) {
+	var run Run
+	key, err := runKey(runId)
+	if err != nil {
+		return run, err
+	}
+	err = db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		if err := item.Value(func(v []byte) error { return json.Unmarshal(v, &run) }); err != nil {
+			return err
+		}
+		fn(&run)
+		jsonData, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return txn.Set(key, jsonData)
+	})
+	return run, err
+}
+
+// ListRuns returns up to limit runs of a job, most recent first
+func ListRuns(jobId string, limit int, db *badger.DB) ([]Run, error) {
+	runs := []Run{}
+	prefix := runPrefix(jobId)
+	err := db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		// Reverse iteration starts from the last key <= seek, so seek past the prefix
+		for it.Seek(append(prefix, 0xff)); it.ValidForPrefix(prefix) && len(runs) < limit; it.Next() {
+			var run Run
+			if err := it.Item().Value(func(v []byte) error { return json.Unmarshal(v, &run) }); err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	return runs, err
+}
+
+func SaveOutput(runId, output string, db *badger.DB) error {
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(outputKey(runId)), []byte(output))
+	})
+}
+
+// LoadOutput returns the output of a run, "" if it has none yet
+func LoadOutput(run Run, db *badger.DB) (string, error) {
+	if run.OutputRef == "" {
+		return "", nil
+	}
+	var output string
+	err := db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(run.OutputRef))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(v []byte) error {
+			output = string(v)
+			return nil
+		})
+	})
+	if err == badger.ErrKeyNotFound {
+		return "", nil
+	}
+	return output, err
+}
+
+// createRun records a newly dispatched run and prunes the oldest run of the
+// job once it has more than runHistory of them
+func createRun(jobId string, n int, scheduledAt time.Time, db *badger.DB) error {
+	run := Run{
+		RunId:       runIdFor(jobId, n),
+		JobId:       jobId,
+		Status:      "QUEUED",
+		ScheduledAt: scheduledAt,
+		ExitCode:    -1,
+		Attempt:     1,
+	}
+	if err := SaveRun(run, db); err != nil {
+		return err
+	}
+	if n <= runHistory {
+		return nil
+	}
+	old := runIdFor(jobId, n-runHistory)
+	return db.Update(func(txn *badger.Txn) error {
+		if err := txn.Delete(runKeyOf(jobId, n-runHistory)); err != nil {
+			return err
+		}
+		return txn.Delete([]byte(outputKey(old)))
+	})
+}
+
+// finishRunRecord stores the result of a run. An empty status keeps the one
+// the run already has, e.g. CANCELLED
+func finishRunRecord(result *pb.JobResult, status string, exitCode int, db *badger.DB) error {
+	if err := SaveOutput(result.RunId, result.Output, db); err != nil {
+		return err
+	}
+	_, err := updateRun(result.RunId, db, func(run *Run) {
+		if status != "" {
+			run.Status = status
+		}
+		applyResult(run, result, exitCode)
+		run.FinishedAt = time.Now()
+		run.OutputRef = outputKey(result.RunId)
+	})
+	return err
+}
+
+// applyResult copies what the worker reported about the latest attempt of a
+// run into it
+func applyResult(run *Run, result *pb.JobResult, exitCode int) {
+	run.ExitCode = exitCode
+	run.OOMKilled = result.OomKilled
+	run.Reason = result.Reason
+	run.ContainerStartedAt = timeOrZero(result.StartedAt)
+	run.ContainerFinishedAt = timeOrZero(result.FinishedAt)
+}
+
+// timeOrZero converts unix seconds to a time, keeping 0 as the zero time
+func timeOrZero(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// recentOutput joins the output of the latest runs of a job, oldest first.
+// It backs the output field of GetJobStatus.
+func recentOutput(jobId string, limit int, db *badger.DB) string {
+	runs, err := ListRuns(jobId, limit, db)
+	if err != nil {
+		log.Printf("[-] Error listing runs of job %s: %v", jobId, err)
+		return ""
+	}
+	var b strings.Builder
+	for i := len(runs) - 1; i >= 0; i-- {
+		output, err := LoadOutput(runs[i], db)
+		if err != nil {
+			log.Printf("[-] Error loading output of run %s: %v", runs[i].RunId, err)
+			continue
+		}
+		if output != "" {
+			b.WriteString(output + "\n")
+		}
+	}
+	return b.String()
+}
+
+// LoadRuns restores the runs that were in flight when the server stopped.
+// Queued runs are still in the durable queue; runs that were executing are
+// queued again, since their workers lost their session with the server. Must
+// be called after LoadJobs and queue.Load.
+func LoadRuns(db *badger.DB) error {
+	runsToFix := []Run{}
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte("run:")
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var run Run
+			if err := it.Item().Value(func(v []byte) error { return json.Unmarshal(v, &run) }); err != nil {
+				return err
+			}
+			if run.Status == "QUEUED" || run.Status == "RUNNING" || run.Status == "RETRYING" {
+				runsToFix = append(runsToFix, run)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, run := range runsToFix {
+		jobContext, ok := store.jobs[run.JobId]
+		switch {
+		case queue.Has(run.RunId):
+			startRun(run.JobId, run.RunId)
+			continue
+		case ok && run.Status == "RUNNING" && run.WorkerId != "":
+			// Its worker may still be executing it and renew its lease when it
+			// reconnects; only if it does not is the run taken back
+			job := proto.Clone(jobContext.Job).(*pb.Job)
+			job.RunId = run.RunId
+			startRun(run.JobId, run.RunId)
+			leases.grant(job, run.WorkerId)
+			log.Printf("[*] Run %s was executing on worker %s when the server stopped, waiting for it to renew its lease", run.RunId, run.WorkerId)
+			continue
+		case ok:
+			job := proto.Clone(jobContext.Job).(*pb.Job)
+			job.RunId = run.RunId
+			startRun(run.JobId, run.RunId)
+			log.Printf("[*] Re-queuing run %s, it was not handed to a worker when the server stopped", run.RunId)
+			requeue(job)
+			continue
+		}
+		// Queued before the queue was durable, or its job is gone
+		run.Status = "FAILED"
+		run.FinishedAt = time.Now()
+		if err := SaveRun(run, db); err != nil {
+			return err
+		}
+	}
+
+	// Jobs that reached their end retire once their last run is done, which
+	// is only known now that the runs in flight are
+	now := time.Now()
+	for jobId, jobContext := range store.jobs {
+		if !settleRetirement(&jobContext) {
+			continue
+		}
+		store.jobs[jobId] = jobContext
+		if err := SaveJob(jobId, jobContext, db); err != nil {
+			return err
+		}
+		if !jobContext.Paused {
+			scheduler.Schedule(jobId, nextFire(jobContext, now))
+		}
+	}
+	return nil
+}
+
+func runToProto(run Run) *pb.Run {
+	return &pb.Run{
+		RunId:       run.RunId,
+		JobId:       run.JobId,
+		WorkerId:    run.WorkerId,
+		Status:      run.Status,
+		ScheduledAt: unixOrZero(run.ScheduledAt),
+		StartedAt:   unixOrZero(run.StartedAt),
+		FinishedAt:  unixOrZero(run.FinishedAt),
+		ExitCode:    int32(run.ExitCode),
+		Attempt:     int32(max(run.Attempt, 1)),
+		Attempts:    attemptsToProto(run.Attempts),
+		OomKilled:   run.OOMKilled,
+		Reason:      run.Reason,
+
+		ContainerStartedAt:  unixOrZero(run.ContainerStartedAt),
+		ContainerFinishedAt: unixOrZero(run.ContainerFinishedAt),
+	}
+}
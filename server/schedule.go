@@ -0,0 +1,563 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // The server image ships without a zoneinfo database
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// oneOff is the schedule of a job that runs once, as soon as possible
+const oneOff = "-1"
+
+// Schedule computes the fire times of a recurring job
+type Schedule interface {
+	// Next returns the first fire time strictly after t, or the zero time if there is none
+	Next(t time.Time) time.Time
+}
+
+// intervalSchedule fires every `every` seconds, aligned to the unix epoch
+// shifted by `offset` seconds
+type intervalSchedule struct {
+	every  int64
+	offset int64
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return time.Unix(((t.Unix()-s.offset)/s.every+1)*s.every+s.offset, 0)
+}
+
+// cronSchedule holds one bitmask per cron field, bit n set = value n allowed
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	loc                                   *time.Location
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondField = cronField{name: "second", min: 0, max: 59}
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as an alias for Sunday
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses the schedule of a recurring job. It accepts a positive
+// number of seconds ("30"), a 5-field (minute first) or 6-field (second first)
+// cron expression, or one of the @hourly/@daily/... macros. Cron expressions
+// are evaluated on the wall clock of loc; intervals ignore it. Cron fields may
+// use Jenkins style H tokens (H, H/15, H(0-29)), which pick a value from a hash
+// of key, so that jobs sharing an expression are spread out but each one keeps
+// stable fire times.
+func ParseSchedule(spec string, loc *time.Location, key string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if n, err := strconv.ParseInt(spec, 10, 64); err == nil {
+		if n <= 0 {
+			return nil, fmt.Errorf("interval must be a positive number of seconds, got %d", n)
+		}
+		return intervalSchedule{every: n}, nil
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown macro %q", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...) // 5-field expressions fire on second 0
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 cron fields, got %d", len(fields))
+	}
+
+	s := &cronSchedule{loc: loc}
+	targets := []*uint64{&s.second, &s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, field := range []cronField{secondField, minuteField, hourField, domField, monthField, dowField} {
+		bits, err := parseCronField(fields[i], field, hashOf(key, field.name))
+		if err != nil {
+			return nil, err
+		}
+		*targets[i] = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	return s, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges (a-b),
+// steps (*/n, a-b/n, a/n) and H tokens into a bitmask
+func parseCronField(expr string, field cronField, hash uint64) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeAndStep := strings.SplitN(part, "/", 2)
+		lo, hi := field.min, field.max
+		hashed := false
+
+		if r := rangeAndStep[0]; r == "H" || strings.HasPrefix(r, "H(") {
+			hashed = true
+			switch {
+			case r != "H":
+				if !strings.HasSuffix(r, ")") {
+					return 0, fmt.Errorf("invalid hash range %q in %s field", r, field.name)
+				}
+				bounds := strings.SplitN(r[2:len(r)-1], "-", 2)
+				if len(bounds) != 2 {
+					return 0, fmt.Errorf("invalid hash range %q in %s field", r, field.name)
+				}
+				var err error
+				if lo, err = parseCronValue(bounds[0], field); err != nil {
+					return 0, err
+				}
+				if hi, err = parseCronValue(bounds[1], field); err != nil {
+					return 0, err
+				}
+			case field.name == domField.name:
+				hi = 28 // Exists in every month
+			case field.name == dowField.name:
+				hi = 6 // Count Sunday once
+			}
+		} else if r != "*" && r != "?" {
+			bounds := strings.SplitN(r, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], field); err != nil {
+				return 0, err
+			}
+			switch {
+			case len(bounds) == 2:
+				if hi, err = parseCronValue(bounds[1], field); err != nil {
+					return 0, err
+				}
+			case len(rangeAndStep) == 1:
+				hi = lo // "a" on its own; "a/n" runs from a to the end of the range
+			}
+		}
+
+		step := 1
+		if len(rangeAndStep) == 2 {
+			n, err := strconv.Atoi(rangeAndStep[1])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", rangeAndStep[1], field.name)
+			}
+			step = n
+		}
+
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q in %s field", part, field.name)
+		}
+		if hashed {
+			if len(rangeAndStep) == 1 {
+				lo += int(hash % uint64(hi-lo+1)) // H picks one value of the range
+				hi = lo
+			} else {
+				lo += int(hash % uint64(min(step, hi-lo+1))) // H/n picks a start within the first step
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, field cronField) (int, error) {
+	if v, ok := field.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, field.name)
+	}
+	if v < field.min || v > field.max {
+		return 0, fmt.Errorf("%s value %d out of range [%d-%d]", field.name, v, field.min, field.max)
+	}
+	return v, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	wall := wallClock(t.In(s.loc))
+	for {
+		wall = s.nextWall(wall)
+		if wall.IsZero() {
+			return wall
+		}
+		if at := resolveWall(wall, s.loc); at.After(t) {
+			return at
+		}
+	}
+}
+
+// resolveWall maps a wall-clock time onto an instant in loc. A time that
+// falls in a DST gap resolves to the end of the gap, and a time in a repeated
+// hour resolves to its first occurrence, so each wall-clock match fires once.
+func resolveWall(wall time.Time, loc *time.Location) time.Time {
+	at := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	start, end := at.ZoneBounds()
+
+	if got := wallClock(at); !got.Equal(wall) {
+		// time.Date moved us out of the gap, either forwards or backwards
+		if got.After(wall) {
+			return start
+		}
+		return end
+	}
+
+	// If the clocks were turned back at the start of this zone, the same wall
+	// time may already have happened once in the previous zone
+	if !start.IsZero() {
+		_, prevOffset := start.Add(-time.Second).Zone()
+		_, offset := at.Zone()
+		if prevOffset > offset {
+			earlier := at.Add(-time.Duration(prevOffset-offset) * time.Second)
+			if earlier.Before(start) && wallClock(earlier).Equal(wall) {
+				return earlier
+			}
+		}
+	}
+	return at
+}
+
+// wallClock returns the wall-clock reading of t as a naive UTC time
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// nextWall returns the first wall-clock time strictly after n that matches the
+// expression. n is a naive wall-clock time expressed in UTC, so the search
+// itself never has to deal with DST. Gives up after 5 years (e.g. "0 0 30 2 *")
+func (s *cronSchedule) nextWall(n time.Time) time.Time {
+	n = n.Add(time.Second)
+	limit := n.Year() + 5
+
+WRAP:
+	if n.Year() > limit {
+		return time.Time{}
+	}
+	for !hasBit(s.month, int(n.Month())) {
+		n = time.Date(n.Year(), n.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		if n.Month() == time.January {
+			goto WRAP
+		}
+	}
+	for !s.dayMatches(n) {
+		n = time.Date(n.Year(), n.Month(), n.Day()+1, 0, 0, 0, 0, time.UTC)
+		if n.Day() == 1 {
+			goto WRAP
+		}
+	}
+	for !hasBit(s.hour, n.Hour()) {
+		n = time.Date(n.Year(), n.Month(), n.Day(), n.Hour()+1, 0, 0, 0, time.UTC)
+		if n.Hour() == 0 {
+			goto WRAP
+		}
+	}
+	for !hasBit(s.minute, n.Minute()) {
+		n = n.Truncate(time.Minute).Add(time.Minute)
+		if n.Minute() == 0 {
+			goto WRAP
+		}
+	}
+	for !hasBit(s.second, n.Second()) {
+		n = n.Add(time.Second)
+		if n.Second() == 0 {
+			goto WRAP
+		}
+	}
+	return n
+}
+
+// dayMatches follows the classic cron rule: if both day of month and day of
+// week are restricted, a day matching either one is enough
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := hasBit(s.dom, t.Day())
+	dow := hasBit(s.dow, int(t.Weekday()))
+	if s.dom == allBits(domField) || s.dow == allBits(dowField) {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func hasBit(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+// allBits is the mask of a "*" field (Sunday only counted once for day of week)
+func allBits(field cronField) uint64 {
+	max := field.max
+	if field.name == dowField.name {
+		max = 6
+	}
+	var bits uint64
+	for v := field.min; v <= max; v++ {
+		bits |= 1 << uint(v)
+	}
+	return bits
+}
+
+// jobLocation returns the time zone the job's schedule is evaluated in
+func jobLocation(job *pb.Job) (*time.Location, error) {
+	if job.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(job.Timezone)
+}
+
+// jobSchedule parses the schedule of a recurring job in its time zone
+func jobSchedule(job *pb.Job) (Schedule, error) {
+	loc, err := jobLocation(job)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := ParseSchedule(job.Schedule, loc, job.Id)
+	if err != nil {
+		return nil, err
+	}
+	if iv, ok := sched.(intervalSchedule); ok && job.Spread {
+		iv.offset = int64(hashOf(job.Id, "spread") % uint64(iv.every))
+		sched = iv
+	}
+	return sched, nil
+}
+
+// hashOf returns a stable hash of a job ID, salted so that different uses of
+// the same ID do not pick correlated values
+func hashOf(key, salt string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(salt))
+	return h.Sum64()
+}
+
+// validateJitter checks the jitter window against the job's schedule. For
+// interval schedules it has to be shorter than the interval, or jittered fires
+// would run into the next one.
+func validateJitter(job *pb.Job) error {
+	if job.JitterSeconds < 0 {
+		return fmt.Errorf("jitter must not be negative, got %d", job.JitterSeconds)
+	}
+	if job.JitterSeconds == 0 || job.Schedule == oneOff {
+		return nil
+	}
+	sched, err := jobSchedule(job)
+	if err != nil {
+		return err
+	}
+	if iv, ok := sched.(intervalSchedule); ok && int64(job.JitterSeconds) >= iv.every {
+		return fmt.Errorf("jitter (%ds) must be shorter than the interval (%ds)", job.JitterSeconds, iv.every)
+	}
+	return nil
+}
+
+// jitterFor returns how long after its nominal time at the job's fire is
+// delayed. It is derived from the job ID and at, so it varies between fires
+// but survives restarts.
+func jitterFor(job *pb.Job, at time.Time) time.Duration {
+	if job.JitterSeconds <= 0 {
+		return 0
+	}
+	window := uint64(job.JitterSeconds) * uint64(time.Second/time.Millisecond)
+	return time.Duration(hashOf(job.Id, strconv.FormatInt(at.Unix(), 10))%window) * time.Millisecond
+}
+
+// dueAt returns when the job's next fire is actually dispatched: NextRun plus
+// its jitter, or the zero time if there is no next fire
+func dueAt(jobContext JobContext) time.Time {
+	if jobContext.NextRun.IsZero() {
+		return time.Time{}
+	}
+	return jobContext.NextRun.Add(jitterFor(jobContext.Job, jobContext.NextRun))
+}
+
+// resolveRunAt validates the one-off timing fields of a submitted job and
+// turns a relative run_in into an absolute run_at, so that a restart does not
+// start the delay over
+func resolveRunAt(job *pb.Job, now time.Time) error {
+	if job.RunAt == "" && job.RunIn == "" {
+		return nil
+	}
+	if job.RunAt != "" && job.RunIn != "" {
+		return fmt.Errorf("run_at and run_in are mutually exclusive")
+	}
+	if job.Schedule == "" {
+		job.Schedule = oneOff
+	}
+	if job.Schedule != oneOff {
+		return fmt.Errorf("run_at and run_in only apply to one-off jobs (schedule %s)", oneOff)
+	}
+
+	if job.RunIn != "" {
+		delay, err := time.ParseDuration(job.RunIn)
+		if err != nil {
+			return fmt.Errorf("invalid run_in %q: %v", job.RunIn, err)
+		}
+		if delay < 0 {
+			return fmt.Errorf("run_in must not be negative, got %s", job.RunIn)
+		}
+		job.RunAt = now.Add(delay).Format(time.RFC3339)
+		job.RunIn = ""
+	}
+	if _, err := time.Parse(time.RFC3339, job.RunAt); err != nil {
+		return fmt.Errorf("invalid run_at %q: %v", job.RunAt, err)
+	}
+	return nil
+}
+
+// scheduleBounds returns the optional not_before/not_after window of a job,
+// zero times meaning unbounded
+func scheduleBounds(job *pb.Job) (notBefore, notAfter time.Time, err error) {
+	if job.NotBefore != "" {
+		if notBefore, err = time.Parse(time.RFC3339, job.NotBefore); err != nil {
+			return notBefore, notAfter, fmt.Errorf("invalid not_before %q: %v", job.NotBefore, err)
+		}
+	}
+	if job.NotAfter != "" {
+		if notAfter, err = time.Parse(time.RFC3339, job.NotAfter); err != nil {
+			return notBefore, notAfter, fmt.Errorf("invalid not_after %q: %v", job.NotAfter, err)
+		}
+	}
+	if !notBefore.IsZero() && !notAfter.IsZero() && !notAfter.After(notBefore) {
+		return notBefore, notAfter, fmt.Errorf("not_after must be later than not_before")
+	}
+	return notBefore, notAfter, nil
+}
+
+// firstRun returns the first fire time of a newly submitted job
+func firstRun(job *pb.Job, now time.Time) (time.Time, error) {
+	if job.MaxRuns < 0 {
+		return time.Time{}, fmt.Errorf("max_runs must not be negative, got %d", job.MaxRuns)
+	}
+	notBefore, notAfter, err := scheduleBounds(job)
+	if err != nil {
+		return time.Time{}, err
+	}
+	start := now
+	if notBefore.After(now) {
+		start = notBefore
+	}
+
+	var next time.Time
+	if job.Schedule == oneOff {
+		next = start // A run_at in the past means "as soon as possible"
+		if job.RunAt != "" {
+			at, err := time.Parse(time.RFC3339, job.RunAt)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if at.After(next) {
+				next = at
+			}
+		}
+	} else {
+		sched, err := jobSchedule(job)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next = sched.Next(start.Add(-time.Nanosecond)) // not_before itself may be a fire time
+	}
+
+	if next.IsZero() || (!notAfter.IsZero() && next.After(notAfter)) {
+		return time.Time{}, fmt.Errorf("schedule %q never fires", job.Schedule)
+	}
+	return next, nil
+}
+
+// nextRun returns the fire time following the one at t, or the zero time if
+// the job should not fire again
+func nextRun(job *pb.Job, t time.Time) time.Time {
+	if job.Schedule == oneOff {
+		return time.Time{}
+	}
+	sched, err := jobSchedule(job)
+	if err != nil {
+		return time.Time{}
+	}
+	next := sched.Next(t)
+	if _, notAfter, _ := scheduleBounds(job); !notAfter.IsZero() && next.After(notAfter) {
+		return time.Time{}
+	}
+	return next
+}
+
+// followingRun returns the fire after the one nominally due at `due`. Fires
+// that are already in the past by now are not replayed; the misfire policy
+// only covers downtime and runs that could not be queued.
+func followingRun(job *pb.Job, due, now time.Time) time.Time {
+	next := nextRun(job, due)
+	if !next.IsZero() && next.Before(now) {
+		next = nextRun(job, now)
+	}
+	return next
+}
+
+// retireReason reports why a recurring job should stop firing for good, or ""
+// if it should keep going
+func retireReason(jobContext JobContext) string {
+	if jobContext.Job.MaxRuns > 0 && jobContext.Runs >= int(jobContext.Job.MaxRuns) {
+		return fmt.Sprintf("reached max runs (%d)", jobContext.Job.MaxRuns)
+	}
+	if jobContext.Job.NotAfter != "" && jobContext.NextRun.IsZero() && len(jobContext.Owed) == 0 {
+		return "schedule window ended"
+	}
+	return ""
+}
+
+// retire moves a job to the terminal RETIRED state; it will not fire again
+func retire(jobContext *JobContext, reason string) {
+	jobContext.Status = "RETIRED"
+	jobContext.RetiredReason = reason
+	jobContext.NextRun = time.Time{}
+	jobContext.Owed = nil
+	log.Printf("[*] Job %s retired: %s", jobContext.Job.Id, reason)
+}
+
+// settleRetirement stops a job that should not fire again, and retires it
+// once none of its runs are in flight, so that its last run still reports
+// its outcome first. It reports whether the job changed. Must be called with
+// store.mu held.
+func settleRetirement(jobContext *JobContext) bool {
+	reason := retireReason(*jobContext)
+	if reason == "" || jobContext.Status == "RETIRED" {
+		return false
+	}
+	if len(store.inflight[jobContext.Job.Id]) > 0 {
+		changed := !jobContext.NextRun.IsZero() || len(jobContext.Owed) > 0
+		jobContext.NextRun = time.Time{}
+		jobContext.Owed = nil
+		return changed
+	}
+	retire(jobContext, reason)
+	return true
+}
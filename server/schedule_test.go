@@ -0,0 +1,270 @@
+package main
+
+import (
+	"math/bits"
+	"testing"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseScheduleNext(t *testing.T) {
+	utc := time.UTC
+	newYork := mustLoadLocation(t, "America/New_York")
+	at := func(loc *time.Location, year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, loc)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		loc  *time.Location
+		from time.Time
+		want []time.Time // Successive fires after from; a zero time means there is none
+	}{
+		{"interval aligned to the epoch", "30", utc, at(utc, 2024, 1, 1, 0, 0, 10),
+			[]time.Time{at(utc, 2024, 1, 1, 0, 0, 30), at(utc, 2024, 1, 1, 0, 1, 0)}},
+		{"@hourly", "@hourly", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 1, 1, 0, 0), at(utc, 2024, 1, 1, 2, 0, 0)}},
+		{"@daily", "@daily", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 2, 0, 0, 0), at(utc, 2024, 1, 3, 0, 0, 0)}},
+		{"@weekly fires on Sunday", "@weekly", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 7, 0, 0, 0), at(utc, 2024, 1, 14, 0, 0, 0)}},
+		{"@monthly", "@monthly", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 2, 1, 0, 0, 0), at(utc, 2024, 3, 1, 0, 0, 0)}},
+		{"@yearly", "@Yearly", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2025, 1, 1, 0, 0, 0)}},
+		{"step", "*/15 * * * *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 1, 0, 15, 0), at(utc, 2024, 1, 1, 0, 30, 0), at(utc, 2024, 1, 1, 0, 45, 0), at(utc, 2024, 1, 1, 1, 0, 0)}},
+		{"range with step", "0 9-17/4 * * *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 1, 9, 0, 0), at(utc, 2024, 1, 1, 13, 0, 0), at(utc, 2024, 1, 1, 17, 0, 0), at(utc, 2024, 1, 2, 9, 0, 0)}},
+		{"start with step", "0 20/2 * * *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 1, 20, 0, 0), at(utc, 2024, 1, 1, 22, 0, 0), at(utc, 2024, 1, 2, 20, 0, 0)}},
+		{"list", "5,10 8 * * *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 1, 8, 5, 0), at(utc, 2024, 1, 1, 8, 10, 0), at(utc, 2024, 1, 2, 8, 5, 0)}},
+		{"day names", "0 0 * * mon-fri", utc, at(utc, 2024, 1, 5, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 8, 0, 0, 0), at(utc, 2024, 1, 9, 0, 0, 0)}},
+		{"month names", "0 12 1 JAN *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 1, 12, 0, 0), at(utc, 2025, 1, 1, 12, 0, 0)}},
+		{"dow 7 is Sunday", "0 0 * * 7", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 7, 0, 0, 0), at(utc, 2024, 1, 14, 0, 0, 0)}},
+		{"seconds field", "30 * * * * *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 1, 0, 0, 30), at(utc, 2024, 1, 1, 0, 1, 30)}},
+		{"dom and dow restricted fire on either", "0 0 13 * 5", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 5, 0, 0, 0), at(utc, 2024, 1, 12, 0, 0, 0), at(utc, 2024, 1, 13, 0, 0, 0), at(utc, 2024, 1, 19, 0, 0, 0)}},
+		{"dom only", "0 0 13 * *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2024, 1, 13, 0, 0, 0), at(utc, 2024, 2, 13, 0, 0, 0)}},
+		{"leap day", "0 0 29 2 *", utc, at(utc, 2024, 3, 1, 0, 0, 0),
+			[]time.Time{at(utc, 2028, 2, 29, 0, 0, 0)}},
+		{"impossible date never fires", "0 0 30 2 *", utc, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{{}}},
+		{"time zone", "0 9 * * *", newYork, at(utc, 2024, 1, 1, 0, 0, 0),
+			[]time.Time{at(newYork, 2024, 1, 1, 9, 0, 0), at(newYork, 2024, 1, 2, 9, 0, 0)}},
+		// Clocks go from 02:00 to 03:00 on 2024-03-10, a fire in the gap moves to its end
+		{"spring forward gap", "30 2 * * *", newYork, at(newYork, 2024, 3, 9, 12, 0, 0),
+			[]time.Time{at(newYork, 2024, 3, 10, 3, 0, 0), at(newYork, 2024, 3, 11, 2, 30, 0)}},
+		{"spring forward hourly", "0 * * * *", newYork, at(newYork, 2024, 3, 10, 0, 30, 0),
+			[]time.Time{at(newYork, 2024, 3, 10, 1, 0, 0), at(newYork, 2024, 3, 10, 3, 0, 0), at(newYork, 2024, 3, 10, 4, 0, 0)}},
+		// Clocks go from 02:00 back to 01:00 on 2024-11-03, the repeated hour fires once
+		{"repeated autumn hour", "30 1 * * *", newYork, at(newYork, 2024, 11, 2, 12, 0, 0),
+			[]time.Time{time.Date(2024, 11, 3, 5, 30, 0, 0, utc), at(newYork, 2024, 11, 4, 1, 30, 0)}},
+		{"repeated autumn hour, every half hour", "*/30 * * * *", newYork, at(newYork, 2024, 11, 3, 0, 45, 0),
+			[]time.Time{time.Date(2024, 11, 3, 5, 0, 0, 0, utc), time.Date(2024, 11, 3, 5, 30, 0, 0, utc), time.Date(2024, 11, 3, 7, 0, 0, 0, utc)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := ParseSchedule(tt.spec, tt.loc, "job")
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q): %v", tt.spec, err)
+			}
+			from := tt.from
+			for i, want := range tt.want {
+				got := sched.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("fire %d of %q after %v = %v, want %v", i+1, tt.spec, from, got, want)
+				}
+				from = got
+			}
+		})
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{
+		"0",
+		"-5",
+		"@fortnightly",
+		"* * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"H(10) * * * *",
+		"H(30-10) * * * *",
+		"H(0-29 * * * *",
+		"x * * * *",
+	} {
+		if _, err := ParseSchedule(spec, time.UTC, "job"); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestParseScheduleHash(t *testing.T) {
+	minutes := func(t *testing.T, spec, key string) uint64 {
+		t.Helper()
+		sched, err := ParseSchedule(spec, time.UTC, key)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q): %v", spec, err)
+		}
+		return sched.(*cronSchedule).minute
+	}
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	tests := []struct {
+		name  string
+		spec  string
+		count int // Minutes picked
+		lo    int // Lowest minute allowed
+		hi    int // Highest minute allowed
+	}{
+		{"H", "H * * * *", 1, 0, 59},
+		{"H range", "H(10-19) * * * *", 1, 10, 19},
+		{"H step", "H/15 * * * *", 4, 0, 59},
+		{"H range with step", "H(0-29)/10 * * * *", 3, 0, 29},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[uint64]bool{}
+			for _, key := range keys {
+				got := minutes(t, tt.spec, key)
+				if got != minutes(t, tt.spec, key) {
+					t.Fatalf("%q picks different minutes for the same key", tt.spec)
+				}
+				if n := bits.OnesCount64(got); n != tt.count {
+					t.Errorf("%q with key %q picks %d minutes, want %d", tt.spec, key, n, tt.count)
+				}
+				if lo, hi := bits.TrailingZeros64(got), 63-bits.LeadingZeros64(got); lo < tt.lo || hi > tt.hi {
+					t.Errorf("%q with key %q picks minutes %d-%d, want within %d-%d", tt.spec, key, lo, hi, tt.lo, tt.hi)
+				}
+				seen[got] = true
+			}
+			if len(seen) == 1 {
+				t.Errorf("%q picks the same minutes for %d keys", tt.spec, len(keys))
+			}
+		})
+	}
+
+	// H/15 keeps the step between the minutes it picks
+	got := minutes(t, "H/15 * * * *", "job")
+	start := bits.TrailingZeros64(got)
+	if start >= 15 || got != 1<<start|1<<(start+15)|1<<(start+30)|1<<(start+45) {
+		t.Errorf("H/15 picks minutes %b, want every 15 minutes from a start below 15", got)
+	}
+
+	// H in the day of month field picks a day every month has
+	for _, key := range keys {
+		sched, err := ParseSchedule("0 0 H * *", time.UTC, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if day := bits.TrailingZeros64(sched.(*cronSchedule).dom); day < 1 || day > 28 {
+			t.Errorf("H day of month with key %q picks day %d, want 1-28", key, day)
+		}
+	}
+}
+
+// TestJobScheduleSpread shifts interval schedules by an offset below the
+// interval that depends on the job ID only
+func TestJobScheduleSpread(t *testing.T) {
+	offsets := map[int64]bool{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		offset := func() int64 {
+			sched, err := jobSchedule(&pb.Job{Id: id, Schedule: "300", Spread: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return sched.(intervalSchedule).offset
+		}
+		got := offset()
+		if got != offset() {
+			t.Fatalf("job %q gets different offsets", id)
+		}
+		if got < 0 || got >= 300 {
+			t.Errorf("job %q gets offset %d, want within [0, 300)", id, got)
+		}
+		offsets[got] = true
+	}
+	if len(offsets) == 1 {
+		t.Error("every job gets the same offset")
+	}
+
+	sched, err := jobSchedule(&pb.Job{Id: "a", Schedule: "300"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset := sched.(intervalSchedule).offset; offset != 0 {
+		t.Errorf("job without --spread gets offset %d, want 0", offset)
+	}
+}
+
+// TestJitterFor keeps the jitter inside the window, stable for a given fire
+// and varying between fires
+func TestJitterFor(t *testing.T) {
+	job := &pb.Job{Id: "job", Schedule: "60", JitterSeconds: 30}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	jitters := map[time.Duration]bool{}
+	for i := 0; i < 20; i++ {
+		at := base.Add(time.Duration(i) * time.Minute)
+		got := jitterFor(job, at)
+		if got != jitterFor(job, at) {
+			t.Fatalf("fire at %v gets different jitters", at)
+		}
+		if got < 0 || got >= 30*time.Second {
+			t.Errorf("fire at %v gets jitter %v, want within [0s, 30s)", at, got)
+		}
+		jitters[got] = true
+	}
+	if len(jitters) == 1 {
+		t.Error("every fire gets the same jitter")
+	}
+	if got := jitterFor(&pb.Job{Id: "job", Schedule: "60"}, base); got != 0 {
+		t.Errorf("job without jitter gets %v, want 0s", got)
+	}
+}
+
+func TestValidateJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     *pb.Job
+		wantErr bool
+	}{
+		{"no jitter", &pb.Job{Id: "job", Schedule: "60"}, false},
+		{"below the interval", &pb.Job{Id: "job", Schedule: "60", JitterSeconds: 59}, false},
+		{"equal to the interval", &pb.Job{Id: "job", Schedule: "60", JitterSeconds: 60}, true},
+		{"above the interval", &pb.Job{Id: "job", Schedule: "60", JitterSeconds: 90}, true},
+		{"negative", &pb.Job{Id: "job", Schedule: "60", JitterSeconds: -1}, true},
+		{"cron is not bounded", &pb.Job{Id: "job", Schedule: "*/5 * * * *", JitterSeconds: 3600}, false},
+		{"one-off", &pb.Job{Id: "job", Schedule: oneOff, JitterSeconds: 3600}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateJitter(tt.job); (err != nil) != tt.wantErr {
+				t.Errorf("validateJitter() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
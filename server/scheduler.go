@@ -0,0 +1,163 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// scheduleEntry is a job waiting for its next fire time
+type scheduleEntry struct {
+	jobId string
+	next  time.Time
+	index int // position in the heap, maintained by fireHeap
+}
+
+// fireHeap is a min-heap of entries ordered by next fire time
+type fireHeap []*scheduleEntry
+
+func (h fireHeap) Len() int           { return len(h) }
+func (h fireHeap) Less(i, j int) bool { return h[i].next.Before(h[j].next) }
+func (h fireHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *fireHeap) Push(x any) {
+	e := x.(*scheduleEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *fireHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	e.index = -1
+	return e
+}
+
+// Scheduler sleeps until the earliest job is due instead of polling the store.
+// It has its own lock, so it never holds store.mu while waiting or sorting;
+// store.mu is only taken by the fire callback, one job at a time.
+type Scheduler struct {
+	mu      sync.Mutex
+	heap    fireHeap
+	entries map[string]*scheduleEntry // jobId -> entry, one entry per job
+	wake    chan struct{}
+}
+
+var scheduler = newScheduler()
+
+func newScheduler() *Scheduler {
+	return &Scheduler{
+		entries: make(map[string]*scheduleEntry),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Schedule sets the next fire time of a job, replacing any previous one.
+// A zero time removes the job from the scheduler.
+func (s *Scheduler) Schedule(jobId string, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[jobId]
+	switch {
+	case next.IsZero():
+		if ok {
+			heap.Remove(&s.heap, e.index)
+			delete(s.entries, jobId)
+		}
+		return
+	case ok:
+		e.next = next
+		heap.Fix(&s.heap, e.index)
+	default:
+		e = &scheduleEntry{jobId: jobId, next: next}
+		heap.Push(&s.heap, e)
+		s.entries[jobId] = e
+	}
+
+	// Only a new earliest entry changes how long Run has to sleep
+	if e.index == 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Remove drops a job from the scheduler
+func (s *Scheduler) Remove(jobId string) {
+	s.Schedule(jobId, time.Time{})
+}
+
+// Len returns the number of jobs waiting to fire
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.heap)
+}
+
+// popDue removes and returns every entry due at or before now
+func (s *Scheduler) popDue(now time.Time) []*scheduleEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*scheduleEntry
+	for len(s.heap) > 0 && !s.heap[0].next.After(now) {
+		e := heap.Pop(&s.heap).(*scheduleEntry)
+		delete(s.entries, e.jobId)
+		due = append(due, e)
+	}
+	return due
+}
+
+// untilNext returns how long to sleep before the earliest entry is due, and
+// false if there is nothing scheduled
+func (s *Scheduler) untilNext() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.heap) == 0 {
+		return 0, false
+	}
+	return time.Until(s.heap[0].next), true
+}
+
+// Run fires due jobs until ctx is cancelled. fire is called without any
+// scheduler lock held and is expected to call Schedule with the job's next
+// fire time, if any.
+func (s *Scheduler) Run(ctx context.Context, fire func(jobId string, at time.Time)) {
+	log.Println("[+] Scheduler started...")
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	for {
+		for _, e := range s.popDue(time.Now()) {
+			fire(e.jobId, e.next)
+		}
+
+		var wait <-chan time.Time
+		if d, ok := s.untilNext(); ok {
+			if d <= 0 {
+				continue
+			}
+			timer.Reset(d)
+			wait = timer.C
+		}
+
+		select {
+		case <-wait:
+		case <-s.wake:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
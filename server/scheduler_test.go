@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+const benchJobs = 100_000
+
+// BenchmarkSchedulerFire100k makes 100k jobs fall due at the same instant and
+// reports how late they fire, how long store.mu is held per fire, and the
+// worst wait seen by a concurrent RPC-like caller of store.mu.
+func BenchmarkSchedulerFire100k(b *testing.B) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+	store.db = db
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var latencies, holds, waits []time.Duration
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		// Every iteration fires the first run of each job again, so start from an empty queue
+		if err := db.DropAll(); err != nil {
+			b.Fatal(err)
+		}
+		queue = newDurableQueue()
+		if err := queue.Load(db); err != nil {
+			b.Fatal(err)
+		}
+		store.jobs = make(map[string]JobContext, benchJobs)
+		store.inflight = make(map[string]map[string]bool)
+		scheduler = newScheduler()
+		due := time.Now().Add(500 * time.Millisecond)
+		for j := 0; j < benchJobs; j++ {
+			id := strconv.Itoa(j)
+			store.jobs[id] = JobContext{Status: "QUEUED", Job: &pb.Job{Id: id, Schedule: "3600"}, NextRun: due}
+			scheduler.Schedule(id, due)
+		}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		wg.Add(benchJobs)
+		runCtx, stop := context.WithCancel(ctx)
+
+		// Stand-in for SubmitJob/GetJobStatus contending for the store
+		done := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				start := time.Now()
+				store.mu.Lock()
+				wait := time.Since(start)
+				store.mu.Unlock()
+				mu.Lock()
+				waits = append(waits, wait)
+				mu.Unlock()
+				time.Sleep(100 * time.Microsecond)
+			}
+		}()
+
+		b.StartTimer()
+		go scheduler.Run(runCtx, func(jobId string, at time.Time) {
+			start := time.Now()
+			fireJob(jobId, at)
+			hold := time.Since(start)
+			mu.Lock()
+			latencies = append(latencies, start.Sub(at))
+			holds = append(holds, hold)
+			mu.Unlock()
+			wg.Done()
+		})
+		wg.Wait()
+		b.StopTimer()
+
+		close(done)
+		stop()
+	}
+
+	report := func(name string, d []time.Duration, q float64) {
+		slices.Sort(d)
+		b.ReportMetric(float64(d[int(q*float64(len(d)-1))].Microseconds()), name)
+	}
+	report("p50-fire-latency-µs", latencies, 0.5)
+	report("p99-fire-latency-µs", latencies, 0.99)
+	report("p50-lock-hold-µs", holds, 0.5)
+	report("max-lock-hold-µs", holds, 1)
+	report("max-store-wait-µs", waits, 1)
+}
+
+// BenchmarkSchedulerSchedule100k measures rescheduling a job while 100k
+// others are waiting in the heap, the cost paid by SubmitJob and every fire.
+func BenchmarkSchedulerSchedule100k(b *testing.B) {
+	s := newScheduler()
+	now := time.Now()
+	for j := 0; j < benchJobs; j++ {
+		s.Schedule(strconv.Itoa(j), now.Add(time.Duration(j)*time.Millisecond))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Schedule(strconv.Itoa(i%benchJobs), now.Add(time.Duration(i)*time.Microsecond))
+	}
+}
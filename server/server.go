@@ -4,20 +4,25 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"flag"
 	"fmt"
 	"log"
 	"net"
 	"os"
-	"strconv"
 	"time"
 
+	badger "github.com/dgraph-io/badger/v4"
 	pb "github.com/dhaval314/epoch/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 
-var jobQueue = make(chan *pb.Job, 100)
+// statusOutputRuns is how many recent runs GetJobStatus includes the output of
+const statusOutputRuns = 10
 
 // type JobStore struct{
 // 	mu sync.Mutex;
@@ -35,45 +40,52 @@ var jobQueue = make(chan *pb.Job, 100)
 // 	jobs : make(map[string]JobContext),
 // }
 
-func runScheduler() {
-    log.Println("[+] Scheduler started...")
-    for {
-        time.Sleep(1 * time.Second)
-		
-        func() {
-            store.mu.Lock()
-            defer store.mu.Unlock() 
-
-            now := time.Now().Unix()
-
-            for jobId, jobContext := range store.jobs {
-                sch, err := strconv.Atoi(jobContext.Job.Schedule)
-                if err != nil {
-                    continue
-                }
-				if sch == -1 {
-					log.Printf("[*] Scheduling one-off Job %s", jobId)
-					select {
-					case jobQueue <- jobContext.Job:
-						log.Println("[+] Job pushed to queue")
-						// Use -2 as sentinel: "already dispatched, do not re-schedule"
-						jobContext.Job.Schedule = "-2"
-						store.jobs[jobId] = jobContext
-					default:
-						log.Println("[-] Job queue full! Skipping.")
-					}
-				} else if sch > 0 && now % int64(sch) == 0 {
-					log.Printf("[*] Scheduling Job %s", jobId)
-					select {
-					case jobQueue <- jobContext.Job:
-						log.Println("[+] Job pushed to queue")
-					default:
-						log.Println("[-] Job queue full! Skipping.")
-					}
-                }
-            }
-        }() 
-    }
+// fireJob is called by the scheduler when a job is due. It pushes the job onto
+// the queue, along with any fires it still owes, and schedules its next fire
+func fireJob(jobId string, at time.Time) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	jobContext, ok := store.jobs[jobId]
+	if !ok || jobContext.Paused { // Deleted or paused since it was scheduled
+		return
+	}
+
+	now := time.Now()
+	if due := dueAt(jobContext); !due.IsZero() && !now.Before(due) {
+		jobContext.Owed = append(jobContext.Owed, jobContext.NextRun)
+		jobContext.NextRun = followingRun(jobContext.Job, jobContext.NextRun, now)
+	}
+
+	for len(jobContext.Owed) > 0 && retireReason(jobContext) == "" {
+		if !admitRun(&jobContext) {
+			break
+		}
+		// Every dispatch is a separate run, so the worker gets its own copy tagged with the run id
+		run := proto.Clone(jobContext.Job).(*pb.Job)
+		run.RunId = runIdFor(jobId, jobContext.Runs+1)
+		log.Printf("[*] Scheduling Job %s (run %s)", jobId, run.RunId)
+		if err := queue.Enqueue(run, 0); err != nil {
+			log.Printf("[-] Failed to queue run %s: %v", run.RunId, err)
+			settleMisfires(&jobContext, "could not be queued")
+			break
+		}
+		log.Println("[+] Job pushed to queue")
+		if err := createRun(jobId, jobContext.Runs+1, jobContext.Owed[0], store.db); err != nil {
+			log.Printf("[-] Failed to save run %s: %v", run.RunId, err)
+		}
+		jobContext.Owed = jobContext.Owed[1:]
+		jobContext.Runs++
+		jobContext.Status = "QUEUED" // Until a worker picks it up
+		startRun(jobId, run.RunId)
+	}
+	settleRetirement(&jobContext)
+
+	store.jobs[jobId] = jobContext
+	if err := SaveJob(jobId, jobContext, store.db); err != nil {
+		log.Printf("[-] Failed to save next run of job %s: %v", jobId, err)
+	}
+	scheduler.Schedule(jobId, nextFire(jobContext, now))
 }
 
 
@@ -84,15 +96,62 @@ type server struct{
 
 // Client calls this function to submit a job to the server
 func (s *server) SubmitJob(ctx context.Context, req *pb.Job) (*pb.JobResponse, error){
+	if err := validateJobId(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "[-] %v", err)
+	}
+	if _, err := jobLocation(req); err != nil {
+		return nil, fmt.Errorf("[-] Invalid time zone %q: %v", req.Timezone, err)
+	}
+	if err := validateMisfirePolicy(req.MisfirePolicy, req.MisfireLimit); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := validateConcurrencyPolicy(req.ConcurrencyPolicy); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := validateRetryPolicy(req.Retry); err != nil {
+		return nil, fmt.Errorf("[-] Invalid retry policy: %v", err)
+	}
+	now := time.Now()
+	if err := resolveRunAt(req, now); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := validateJitter(req); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := validateTimeout(req); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := validateResources(req); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := validatePlacement(req); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := validateLimits(req); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	applyLimitDefaults(req)
+	next, err := firstRun(req, now)
+	if err != nil {
+		return nil, fmt.Errorf("[-] Invalid schedule %q: %v", req.Schedule, err)
+	}
+
 	store.mu.Lock() // No two goroutines can access the hashmap at the same time
 	defer store.mu.Unlock()
 
+	// Replacing a job would restart its run numbering over the runs it already has
+	if _, ok := store.jobs[req.Id]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "[-] Job %s already exists", req.Id)
+	}
+
 	new_context := JobContext{
 		Status: "QUEUED",
-		Output: "",
 		Job: req,
+		CreatedAt: now,
+		NextRun: next,
 	}
 	store.jobs[req.Id] = new_context
+	scheduler.Schedule(req.Id, next) // Wakes the scheduler if this job is now the earliest
 
 	// Save the job in the DB
 	if err := SaveJob(req.Id, new_context, store.db); err != nil {
@@ -102,75 +161,668 @@ func (s *server) SubmitJob(ctx context.Context, req *pb.Job) (*pb.JobResponse, e
 	return &pb.JobResponse{Success: true, Message: "[+] Job Accepted by the server", Id: req.Id}, nil // server response
 }
 
-// Worker calls this function to connect to the server 
-func (s *server) ConnectWorker(req *pb.WorkerHello, stream grpc.ServerStreamingServer[pb.Job]) (error){
-	log.Printf("[+] Worker %s connected", req.WorkerId)
+// Worker calls this function to connect to the server. The worker opens with
+// a WorkerHello, then acks every run sent to it and heartbeats the runs it
+// holds; the server answers both with leases
+func (s *server) ConnectWorker(stream grpc.BidiStreamingServer[pb.WorkerMessage, pb.ServerMessage]) (error){
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	hello := first.GetHello()
+	if hello == nil {
+		return fmt.Errorf("[-] Expected WorkerHello as the first message")
+	}
+	workerId := hello.WorkerId
+	session, cordoned := registry.connected(hello)
+	defer registry.disconnected(workerId, session)
+	conn := workers.add(hello, cordoned)
+	defer workers.remove(workerId, conn)
+	messages := conn.messages
+	log.Printf("[+] Worker %s connected with %d slots, %g CPUs, %d MB of memory", workerId, conn.slots, conn.cpus, conn.memoryMb)
+	if hello.Version != "" {
+		log.Printf("[+] Worker %s version: %s", workerId, hello.Version)
+	}
+	if cordoned {
+		log.Printf("[*] Worker %s is cordoned, it gets no runs until uncordoned", workerId)
+	}
+	if len(conn.labels) > 0 {
+		log.Printf("[+] Worker %s labels: %v", workerId, conn.labels)
+	}
+
+	// Acks and heartbeats are read here, their leases are sent by the loop below
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			var granted []*pb.Lease
+			var reply *pb.ServerMessage
+			switch m := msg.Message.(type) {
+			case *pb.WorkerMessage_Ack:
+				// The run leaves the queue for good, from now on its lease keeps track of it
+				job, err := queue.Ack(m.Ack.RunId, workerId)
+				if err == nil {
+					granted = []*pb.Lease{leases.grant(job, workerId)}
+					break
+				}
+				if holder, ok := leases.holder(m.Ack.RunId); ok && holder == workerId {
+					continue // Acked twice
+				}
+				// Too late: the run went back to the queue or to another
+				// worker, so this worker's copy would run without a lease
+				log.Printf("[-] Rejecting ack from worker %s, stopping its copy: %v", workerId, err)
+				reply = &pb.ServerMessage{Message: &pb.ServerMessage_Cancel{Cancel: &pb.CancelRunRequest{RunId: m.Ack.RunId}}}
+			case *pb.WorkerMessage_Heartbeat:
+				registry.heartbeat(workerId, session)
+				workers.sync(conn, m.Heartbeat.RunIds)
+				granted = leases.renew(workerId, m.Heartbeat.RunIds)
+			case *pb.WorkerMessage_Drain:
+				log.Printf("[*] Worker %s is shutting down, draining it", workerId)
+				workers.drain(workerId)
+			}
+			if len(granted) > 0 {
+				reply = &pb.ServerMessage{Message: &pb.ServerMessage_Leases{Leases: &pb.LeaseGrant{Leases: granted}}}
+			}
+			if reply == nil {
+				continue
+			}
+			select {
+			case messages <- reply:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	for {
+		// Leases and cancels go first, so a long queue does not hold them up
 		select {
-		case job := <-jobQueue: // If a new job enters the channel, it is sent to the worker
-			log.Printf("[*] Dispatching Job %s to Worker %s", job.Id, req.WorkerId)
-			err := stream.Send(job)
+		case msg := <-messages:
+			if err := stream.Send(msg); err != nil {
+				log.Printf("[-] Error sending message to worker %s: %v", workerId, err)
+				return err
+			}
+			continue
+		case <-ctx.Done():
+			log.Printf("[-] Worker %s disconnected.", workerId)
+			return nil
+		default:
+		}
+
+		// A drained worker is told to go once its last run is in
+		freed := workers.Wait()
+		if workers.drained(conn) {
+			log.Printf("[*] Worker %s drained, disconnecting it", workerId)
+			msg := &pb.ServerMessage{Message: &pb.ServerMessage_Disconnect{Disconnect: &pb.Disconnect{Reason: "drained"}}}
+			if err := stream.Send(msg); err != nil {
+				log.Printf("[-] Error sending message to worker %s: %v", workerId, err)
+				return err
+			}
+			return nil
+		}
+
+		// A busy or cordoned worker waits for one of its runs to finish, or to
+		// be uncordoned
+		if !workers.hasSlot(conn) {
+			select {
+			case <-freed:
+			case msg := <-messages:
+				if err := stream.Send(msg); err != nil {
+					log.Printf("[-] Error sending message to worker %s: %v", workerId, err)
+					return err
+				}
+			case <-ctx.Done():
+				log.Printf("[-] Worker %s disconnected.", workerId)
+				return nil
+			}
+			continue
+		}
+
+		// Runs the worker lacks the resources for, or that fit another worker
+		// better, stay queued
+		ready := queue.Wait()
+		job, visible, err := queue.Dequeue(workerId, func(job *pb.Job) bool { return workers.reserve(conn, job) })
+		if err != nil {
+			log.Printf("[-] Error reading the job queue: %v", err)
+			if job != nil {
+				workers.release(job.RunId)
+				job = nil
+			}
+		}
+		if job != nil {
+			if !claimRun(job, workerId) {
+				workers.release(job.RunId)
+				log.Printf("[*] Skipping run %s, it was cancelled while queued", job.RunId)
+				if err := queue.Remove(job.RunId); err != nil {
+					log.Printf("[-] Failed to drop run %s from the queue: %v", job.RunId, err)
+				}
+				continue
+			}
+			log.Printf("[*] Dispatching Job %s to Worker %s", job.Id, workerId)
+			err := stream.Send(&pb.ServerMessage{Message: &pb.ServerMessage_Job{Job: job}})
 			if err != nil {
-				log.Printf("[-] Error sending job to worker %s, re-queuing: %v", req.WorkerId, err)
-				// Put the job back so another worker can pick it up.
-				select {
-				case jobQueue <- job:
-				default:
-					log.Printf("[-] Re-queue failed: channel full, job %s lost", job.Id)
+				log.Printf("[-] Error sending job to worker %s, re-queuing: %v", workerId, err)
+				// Make the run visible again so another worker can pick it up
+				if err := queue.Release(job.RunId); err != nil {
+					log.Printf("[-] Failed to release run %s: %v", job.RunId, err)
 				}
 				return err
 			}
-		case <-stream.Context().Done():
-			log.Printf("[-] Worker %s disconnected.", req.WorkerId)
+			continue
+		}
+
+		// Nothing to hand out: wait for a new run, a run another worker did not
+		// ack, or for resources or workers to change
+		wait := time.Hour
+		if !visible.IsZero() {
+			wait = time.Until(visible)
+		} else if err != nil {
+			wait = time.Second
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ready:
+		case <-freed:
+		case <-timer.C:
+		case msg := <-messages:
+			if err := stream.Send(msg); err != nil {
+				log.Printf("[-] Error sending message to worker %s: %v", workerId, err)
+				return err
+			}
+		case <-ctx.Done():
+			// Runs it holds are taken back once their leases expire, unless it reconnects and renews them
+			log.Printf("[-] Worker %s disconnected.", workerId)
 			return nil
 		}
+		timer.Stop()
+	}
+}
+
+// claimRun marks a run and its job RUNNING on the worker it is handed to,
+// before it is sent, so a cancel issued from then on reaches that worker. It
+// reports false if the run is no longer in flight, e.g. it was cancelled
+// while queued.
+func claimRun(job *pb.Job, workerId string) bool {
+	if job.RunId == "" {
+		return true
+	}
+	store.mu.Lock() // Serializes with fireJob, which creates the run record after enqueueing it
+	defer store.mu.Unlock()
+	if !store.inflight[job.Id][job.RunId] {
+		return false
+	}
+	now := time.Now()
+	_, err := updateRun(job.RunId, store.db, func(run *Run) {
+		run.Status = "RUNNING"
+		run.WorkerId = workerId
+		run.StartedAt = now
+	})
+	if err != nil {
+		log.Printf("[-] Failed to save dispatch of run %s: %v", job.RunId, err)
 	}
+	setRunning(job.Id, job.RunId, workerId, now)
+	return true
 }
 
 // Worker calls this function to let the server know that the job has been completed
 func (s* server) CompleteJob(ctx context.Context, req *pb.JobResult)(*pb.Empty, error){
 	store.mu.Lock()
 	defer store.mu.Unlock()
+
+	// A worker whose lease lapsed, or whose ack came too late, may still
+	// report a run that went to another worker meanwhile. Its result is
+	// ignored, and the lease and slot of the other worker are left alone.
+	if req.RunId != "" && req.WorkerId != "" && !holdsRun(req.WorkerId, req.RunId) {
+		log.Printf("[*] Ignoring result of run %s from worker %s, which no longer holds it", req.RunId, req.WorkerId)
+		workers.releaseOn(req.WorkerId, req.RunId)
+		return &pb.Empty{}, nil
+	}
 	
-	// Retrieve job Id, status, and job context
-	status := req.Success
+	// Retrieve job Id and job context
 	jobId := req.JobId
-	jobContext := store.jobs[jobId]
+	jobContext, ok := store.jobs[jobId]
+	if !ok {
+		log.Printf("[*] Ignoring result of run %s, job %s was deleted", req.RunId, jobId)
+		if req.RunId != "" {
+			leases.release(req.RunId)
+			workers.release(req.RunId)
+		}
+		return &pb.Empty{}, nil
+	}
 
-	// Update the job status accordingly
-	if status == true{
-		jobContext.Status = "COMPLETED"
-		jobContext.Output += req.Output + "\n"
-		store.jobs[jobId] = jobContext
-		if err := SaveJob(req.JobId, jobContext, store.db); err != nil { 
-    		log.Printf("[-] Failed to save job completion: %v", err)
+	// The status follows the exit code; success only matters for workers
+	// that do not report one
+	exitCode := int(req.ExitCode)
+	if !req.Success && exitCode == 0 {
+		exitCode = -1 // Failed without an exit code, the container could not be run
+	}
+	runStatus := "COMPLETED"
+	switch {
+	case req.TimedOut:
+		runStatus = "TIMED_OUT"
+	case exitCode != 0 || req.OomKilled:
+		runStatus = "FAILED"
+	}
+
+	// Results of runs that were replaced or cancelled no longer count; only
+	// their output is kept, under the status they were given
+	counted := req.RunId == "" || store.inflight[jobId][req.RunId]
+	if !counted {
+		log.Printf("[*] Ignoring result of replaced or cancelled run %s", req.RunId)
+		runStatus = ""
+	}
+	if req.RunId != "" {
+		leases.release(req.RunId)
+		workers.release(req.RunId)
+	}
+
+	// A failed attempt is retried if the job's retry policy allows, the run
+	// and job only fail once the retries are used up, and the run is then
+	// dead-lettered
+	deadLetter := ""
+	if counted && (runStatus == "FAILED" || runStatus == "TIMED_OUT") && req.RunId != "" {
+		run, err := LoadRun(req.RunId, store.db)
+		if err != nil {
+			log.Printf("[-] Failed to load run %s: %v", req.RunId, err)
+		} else if delay, ok := retryDelay(jobContext.Job.Retry, retryAttempt(run), exitCode); !ok {
+			deadLetter = deadLetterReason(jobContext.Job.Retry, retryAttempt(run), failureCause(jobContext.Job, req, exitCode))
+		} else {
+			if err := retryRun(&jobContext, req, exitCode, delay); err != nil {
+				log.Printf("[-] Failed to retry run %s: %v", req.RunId, err)
+				deadLetter = fmt.Sprintf("the retry could not be queued: %v", err)
+			} else {
+				store.jobs[jobId] = jobContext
+				if err := SaveJob(jobId, jobContext, store.db); err != nil {
+					log.Printf("[-] Failed to save job %s: %v", jobId, err)
+				}
+				return &pb.Empty{}, nil
+			}
 		}
-		log.Printf("[+] Job %v : %v", jobContext.Job.Id, jobContext.Status)
-	} else{
-		jobContext.Status = "FAILED"
-		jobContext.Output = req.Output
-		store.jobs[jobId] = jobContext
-		log.Printf("[+] Job %v : %v", jobContext.Job.Id, jobContext.Status)
 	}
 
+	if counted && req.RunId != "" {
+		finishRun(jobId, req.RunId)
+	}
+	if req.RunId != "" {
+		if err := finishRunRecord(req, runStatus, exitCode, store.db); err != nil {
+			log.Printf("[-] Failed to save result of run %s: %v", req.RunId, err)
+		}
+	}
+	if deadLetter != "" {
+		deadLetterRun(jobContext, req.RunId, deadLetter, req.Output)
+	}
+	if !counted {
+		return &pb.Empty{}, nil
+	}
+
+	// Update the job status accordingly
+	// The last run of a retired job still reports back, but the job stays retired
+	if jobContext.Status != "RETIRED" {
+		jobContext.Status = runStatus
+	}
+	settleRetirement(&jobContext) // This may have been its last run
+	store.jobs[jobId] = jobContext
+	if err := SaveJob(req.JobId, jobContext, store.db); err != nil { 
+		log.Printf("[-] Failed to save job completion: %v", err)
+	}
+	log.Printf("[+] Job %v : %v", jobContext.Job.Id, jobContext.Status)
+
 	return &pb.Empty{}, nil
 }
 
 func (s* server) GetJobStatus(ctx context.Context, req *pb.JobStatusRequest)(*pb.JobStatusResponse, error){
 	store.mu.Lock()
-	defer store.mu.Unlock()
 	jobContext, ok := store.jobs[req.JobId]
+	store.mu.Unlock()
 	if !ok {
     	return nil, fmt.Errorf("[-] Job not found")
 	}
 
+	misfires := make([]*pb.Misfire, 0, len(jobContext.Misfires))
+	for _, m := range jobContext.Misfires {
+		misfires = append(misfires, &pb.Misfire{ScheduledAt: m.ScheduledAt.Unix(), Reason: m.Reason})
+	}
+
 	return &pb.JobStatusResponse{JobId: req.JobId,
 								 Status: jobContext.Status,
-								 Output: string(jobContext.Output),}, nil				
+								 Output: recentOutput(req.JobId, statusOutputRuns, store.db), // Read from badger outside the lock
+								 Misfires: misfires,
+								 MisfireCount: int32(jobContext.MisfireCount),
+								 NextRun: unixOrZero(jobContext.NextRun),
+								 Runs: int32(jobContext.Runs),
+								 RetiredReason: jobContext.RetiredReason,
+								 Paused: jobContext.Paused,
+								 RunId: jobContext.RunId,
+								 WorkerId: jobContext.WorkerId,
+								 StartedAt: unixOrZero(jobContext.StartedAt),}, nil
+
+}
+
+func (s* server) ListRuns(ctx context.Context, req *pb.ListRunsRequest)(*pb.ListRunsResponse, error){
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 20
+	}
+	runs, err := ListRuns(req.JobId, limit, store.db)
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error listing runs: %v", err)
+	}
+
+	resp := &pb.ListRunsResponse{}
+	for _, run := range runs {
+		resp.Runs = append(resp.Runs, runToProto(run))
+	}
+	return resp, nil
+}
+
+func (s* server) GetRun(ctx context.Context, req *pb.GetRunRequest)(*pb.Run, error){
+	run, err := LoadRun(req.RunId, store.db)
+	if err == badger.ErrKeyNotFound {
+		return nil, fmt.Errorf("[-] Run not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error loading run: %v", err)
+	}
+	output, err := LoadOutput(run, store.db)
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error loading run output: %v", err)
+	}
+
+	resp := runToProto(run)
+	resp.Output = output
+	return resp, nil
+}
+
+func (s* server) ListJobs(ctx context.Context, req *pb.ListJobsRequest)(*pb.ListJobsResponse, error){
+	jobs, nextToken, err := ListJobs(req, store.db)
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error listing jobs: %v", err)
+	}
+
+	resp := &pb.ListJobsResponse{NextPageToken: nextToken}
+	for _, jobContext := range jobs {
+		resp.Jobs = append(resp.Jobs, jobSummary(jobContext))
+	}
+	return resp, nil
+}
+
+func (s* server) DeleteJob(ctx context.Context, req *pb.DeleteJobRequest)(*pb.JobResponse, error){
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.jobs[req.JobId]; !ok {
+		return nil, fmt.Errorf("[-] Job not found")
+	}
+	// Runs still in flight are stopped as if cancelled, so their queue items,
+	// leases and worker slots do not outlive the job and cannot be mistaken
+	// for the runs of a new job with the same ID
+	for runId := range store.inflight[req.JobId] {
+		run, err := LoadRun(runId, store.db)
+		if err != nil {
+			log.Printf("[-] Failed to load run %s: %v", runId, err)
+			run = Run{RunId: runId}
+		}
+		if _, err := stopRun(req.JobId, run, "CANCELLED"); err != nil {
+			log.Printf("[-] Failed to stop run %s: %v", runId, err)
+		}
+	}
+	if err := DeleteJobRecords(req.JobId, store.db); err != nil {
+		return nil, fmt.Errorf("[-] Error deleting job: %v", err)
+	}
+	delete(store.jobs, req.JobId)
+	delete(store.inflight, req.JobId)
+	scheduler.Remove(req.JobId)
+
+	log.Printf("[+] Deleted Job %v", req.JobId)
+	return &pb.JobResponse{Success: true, Message: "[+] Job deleted", Id: req.JobId}, nil
+}
+
+func (s* server) UpdateJob(ctx context.Context, req *pb.UpdateJobRequest)(*pb.JobResponse, error){
+	if req.Job == nil {
+		return nil, fmt.Errorf("[-] No job given")
+	}
+	jobId := req.Job.Id
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	jobContext, ok := store.jobs[jobId]
+	if !ok {
+		return nil, fmt.Errorf("[-] Job not found")
+	}
+	job, err := applyUpdate(jobContext.Job, req.Job, req.UpdateMask)
+	if err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+	if err := updateJobContext(&jobContext, job, req.UpdateMask, time.Now()); err != nil {
+		return nil, fmt.Errorf("[-] %v", err)
+	}
+
+	// The store only changes once the record is saved
+	if err := SaveJob(jobId, jobContext, store.db); err != nil {
+		return nil, fmt.Errorf("[-] Error saving job: %v", err)
+	}
+	store.jobs[jobId] = jobContext
+	if !jobContext.Paused {
+		scheduler.Schedule(jobId, nextFire(jobContext, time.Now()))
+	}
+
+	log.Printf("[+] Updated Job %v : %v", jobId, req.UpdateMask)
+	return &pb.JobResponse{Success: true, Message: "[+] Job updated", Id: jobId}, nil
+}
+
+func (s* server) PauseJob(ctx context.Context, req *pb.PauseJobRequest)(*pb.JobResponse, error){
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	jobContext, ok := store.jobs[req.JobId]
+	if !ok {
+		return nil, fmt.Errorf("[-] Job not found")
+	}
+	if jobContext.Status == "RETIRED" {
+		return nil, fmt.Errorf("[-] Job is retired")
+	}
+	if jobContext.Paused {
+		return &pb.JobResponse{Success: true, Message: "[*] Job already paused", Id: req.JobId}, nil
+	}
+
+	jobContext.Paused = true
+	if err := SaveJob(req.JobId, jobContext, store.db); err != nil {
+		return nil, fmt.Errorf("[-] Error saving job: %v", err)
+	}
+	store.jobs[req.JobId] = jobContext
+	scheduler.Remove(req.JobId) // Runs already dispatched are left to finish
 
+	log.Printf("[+] Paused Job %v", req.JobId)
+	return &pb.JobResponse{Success: true, Message: "[+] Job paused", Id: req.JobId}, nil
+}
+
+func (s* server) ResumeJob(ctx context.Context, req *pb.ResumeJobRequest)(*pb.JobResponse, error){
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	jobContext, ok := store.jobs[req.JobId]
+	if !ok {
+		return nil, fmt.Errorf("[-] Job not found")
+	}
+	if !jobContext.Paused {
+		return &pb.JobResponse{Success: true, Message: "[*] Job is not paused", Id: req.JobId}, nil
+	}
+
+	now := time.Now()
+	resumeJob(&jobContext, now)
+	if err := SaveJob(req.JobId, jobContext, store.db); err != nil {
+		return nil, fmt.Errorf("[-] Error saving job: %v", err)
+	}
+	store.jobs[req.JobId] = jobContext
+	scheduler.Schedule(req.JobId, nextFire(jobContext, now))
+
+	log.Printf("[+] Resumed Job %v", req.JobId)
+	return &pb.JobResponse{Success: true, Message: "[+] Job resumed", Id: req.JobId}, nil
+}
+
+func (s* server) CancelRun(ctx context.Context, req *pb.CancelRunRequest)(*pb.JobResponse, error){
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	message, err := cancelRun(req.RunId)
+	if err == badger.ErrKeyNotFound {
+		return nil, fmt.Errorf("[-] Run not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error cancelling run: %v", err)
+	}
+	return &pb.JobResponse{Success: true, Message: message, Id: req.RunId}, nil
+}
+
+func (s* server) GetQueueStats(ctx context.Context, req *pb.Empty)(*pb.QueueStats, error){
+	depth, delivering, delayed, oldest, err := queue.Stats()
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error reading the job queue: %v", err)
+	}
+	stats := &pb.QueueStats{Depth: int32(depth), Delivering: int32(delivering), Delayed: int32(delayed), OldestEnqueuedAt: unixOrZero(oldest)}
+	if !oldest.IsZero() {
+		stats.OldestAgeSeconds = int64(time.Since(oldest).Seconds())
+	}
+	return stats, nil
+}
+
+func (s* server) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest)(*pb.ListDeadLettersResponse, error){
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+	dls, err := ListDeadLetters(req.JobId, limit, store.db)
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error listing dead letters: %v", err)
+	}
+	resp := &pb.ListDeadLettersResponse{}
+	for _, dl := range dls {
+		dl.Output = "" // Only shown by GetDeadLetter
+		resp.DeadLetters = append(resp.DeadLetters, deadLetterToProto(dl))
+	}
+	return resp, nil
+}
+
+func (s* server) GetDeadLetter(ctx context.Context, req *pb.GetDeadLetterRequest)(*pb.DeadLetter, error){
+	dl, err := LoadDeadLetter(req.RunId, store.db)
+	if err == badger.ErrKeyNotFound {
+		return nil, fmt.Errorf("[-] Run %s is not dead-lettered", req.RunId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[-] Error loading dead letter: %v", err)
+	}
+	return deadLetterToProto(dl), nil
+}
+
+func (s* server) RequeueDeadLetters(ctx context.Context, req *pb.RequeueDeadLettersRequest)(*pb.RequeueDeadLettersResponse, error){
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	runIds := []string{req.RunId}
+	if req.RunId == "" {
+		if req.JobId == "" && !req.All {
+			return nil, fmt.Errorf("[-] Pass a run id, a job id or all")
+		}
+		dls, err := ListDeadLetters(req.JobId, 0, store.db)
+		if err != nil {
+			return nil, fmt.Errorf("[-] Error listing dead letters: %v", err)
+		}
+		runIds = runIds[:0]
+		for i := len(dls) - 1; i >= 0; i-- { // Oldest failure first
+			runIds = append(runIds, dls[i].RunId)
+		}
+	}
+
+	resp := &pb.RequeueDeadLettersResponse{}
+	for _, runId := range runIds {
+		err := requeueDeadLetter(runId)
+		if err == badger.ErrKeyNotFound {
+			err = fmt.Errorf("not dead-lettered")
+		}
+		if err != nil {
+			resp.Skipped = append(resp.Skipped, fmt.Sprintf("%s: %v", runId, err))
+			continue
+		}
+		resp.RunIds = append(resp.RunIds, runId)
+	}
+	return resp, nil
+}
+
+func (s* server) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest)(*pb.ListWorkersResponse, error){
+	now := time.Now()
+	resp := &pb.ListWorkersResponse{}
+	for _, record := range registry.list() {
+		worker := workerToProto(record, now)
+		if matchesWorkerStatus(worker.Status, req.Status) {
+			resp.Workers = append(resp.Workers, worker)
+		}
+	}
+	return resp, nil
+}
+
+func (s* server) GetWorker(ctx context.Context, req *pb.GetWorkerRequest)(*pb.Worker, error){
+	record, ok := registry.get(req.WorkerId)
+	if !ok {
+		return nil, fmt.Errorf("[-] Worker not found")
+	}
+	return workerToProto(record, time.Now()), nil
+}
+
+func (s* server) CordonWorker(ctx context.Context, req *pb.CordonWorkerRequest)(*pb.Worker, error){
+	record, ok := registry.cordon(req.WorkerId, true)
+	if !ok {
+		return nil, fmt.Errorf("[-] Worker not found")
+	}
+	workers.cordon(req.WorkerId, true)
+	log.Printf("[*] Cordoned worker %s", req.WorkerId)
+	return workerToProto(record, time.Now()), nil
+}
+
+func (s* server) UncordonWorker(ctx context.Context, req *pb.UncordonWorkerRequest)(*pb.Worker, error){
+	if workers.isDraining(req.WorkerId) {
+		return nil, fmt.Errorf("[-] Worker is draining, it is disconnected once its runs finish")
+	}
+	record, ok := registry.cordon(req.WorkerId, false)
+	if !ok {
+		return nil, fmt.Errorf("[-] Worker not found")
+	}
+	workers.cordon(req.WorkerId, false)
+	log.Printf("[*] Uncordoned worker %s", req.WorkerId)
+	return workerToProto(record, time.Now()), nil
+}
+
+// DrainWorker cordons a worker and, if it is connected, disconnects it once
+// the runs it holds are finished
+func (s* server) DrainWorker(ctx context.Context, req *pb.DrainWorkerRequest)(*pb.Worker, error){
+	record, ok := registry.cordon(req.WorkerId, true)
+	if !ok {
+		return nil, fmt.Errorf("[-] Worker not found")
+	}
+	workers.cordon(req.WorkerId, true)
+	if workers.drain(req.WorkerId) {
+		log.Printf("[*] Draining worker %s", req.WorkerId)
+	}
+	return workerToProto(record, time.Now()), nil
+}
+
+// unixOrZero converts t to unix seconds, keeping the zero time as 0
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
 }
 
 func main(){
+	registerLimitFlags()
+	flag.Parse()
+
 	port := ":50051"
 	lis, err := net.Listen("tcp", port)
 	if err != nil{
@@ -216,15 +868,25 @@ func main(){
 	// Wrap the tls.Config
 	creds := credentials.NewTLS(tlsConfig)
 
-	go runScheduler()
-
 	if store.db, err = CreateDB(); err != nil{
 		log.Printf("[-] Error creating database: %v", err)
 	}
 	if err = LoadJobs(store.db); err!=nil{
 		log.Printf("[-] Error loading jobs into hashmap: %v", err)
 	}
+	if err = queue.Load(store.db); err!=nil{
+		log.Printf("[-] Error loading job queue: %v", err)
+	}
+	if err = registry.Load(store.db); err!=nil{
+		log.Printf("[-] Error loading workers: %v", err)
+	}
+	if err = LoadRuns(store.db); err!=nil{
+		log.Printf("[-] Error repairing runs: %v", err)
+	}
 	defer store.db.Close()
+
+	go scheduler.Run(context.Background(), fireJob)
+	go reclaimLeases(context.Background())
 	
 	grpcServer := grpc.NewServer(grpc.Creds(creds)) // Create a new grpc server using the credentials
 	pb.RegisterSchedulerServer(grpcServer, &server{})
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"sync"
+	"time"
 	"encoding/json"
 
 	badger "github.com/dgraph-io/badger/v4"
@@ -12,18 +13,30 @@ import (
 type JobStore struct{
 	mu sync.Mutex;
 	jobs map[string]JobContext // HashMap to store all the jobs
+	inflight map[string]map[string]bool // jobId -> runs dispatched but not completed yet
 	db *badger.DB
 }
 
 type JobContext struct{
-	Status string;
-	Output string;
+	Status string; // Outcome of the latest run, or RETIRED. Outputs live in the run records
 	Job *pb.Job
+	CreatedAt time.Time
+	NextRun time.Time // Next time the job fires, zero if it never fires again
+	Owed []time.Time // Fires that came due but could not be dispatched yet
+	Misfires []Misfire // Most recent fires skipped by the misfire policy
+	MisfireCount int
+	Runs int // Number of times the job has been dispatched
+	RetiredReason string // Why the job was moved to RETIRED
+	Paused bool // Paused jobs keep their status but are not scheduled
+	RunId string // Latest run handed to a worker
+	WorkerId string // Worker the latest run was handed to
+	StartedAt time.Time // When the latest run was handed to its worker
 }
 
 // Initialize the JobStore struct
 var store = JobStore{
 	jobs : make(map[string]JobContext),
+	inflight : make(map[string]map[string]bool),
 }
 
 
@@ -39,9 +52,47 @@ func SaveJob(id string, req JobContext, db *badger.DB) error {
     })
 }
 
+// setRunning records that a run of a job was handed to a worker. Must be
+// called with store.mu held.
+func setRunning(jobId, runId, workerId string, startedAt time.Time) {
+	jobContext, ok := store.jobs[jobId]
+	if !ok {
+		return
+	}
+	if jobContext.Status != "RETIRED" {
+		jobContext.Status = "RUNNING"
+	}
+	jobContext.RunId = runId
+	jobContext.WorkerId = workerId
+	jobContext.StartedAt = startedAt
+	store.jobs[jobId] = jobContext
+	if err := SaveJob(jobId, jobContext, store.db); err != nil {
+		log.Printf("[-] Failed to save dispatch of job %s: %v", jobId, err)
+	}
+}
+
+// setRequeued undoes setRunning when a run is taken back from its worker.
+// Must be called with store.mu held.
+func setRequeued(jobId, runId string) {
+	jobContext, ok := store.jobs[jobId]
+	if !ok || jobContext.RunId != runId {
+		return
+	}
+	if jobContext.Status == "RUNNING" {
+		jobContext.Status = "QUEUED"
+	}
+	jobContext.WorkerId = ""
+	jobContext.StartedAt = time.Time{}
+	store.jobs[jobId] = jobContext
+	if err := SaveJob(jobId, jobContext, store.db); err != nil {
+		log.Printf("[-] Failed to save job %s: %v", jobId, err)
+	}
+}
+
 func LoadJobs(db *badger.DB) error{
-	// Slice to store zombie jobs, which are later marked as failed
+	// Slice to store jobs whose record has to be rewritten (zombies and jobs that missed fires)
 	jobsToFix := []JobContext{}
+	now := time.Now()
 
 	err := db.View(func(txn *badger.Txn) error { 
 
@@ -59,11 +110,25 @@ func LoadJobs(db *badger.DB) error{
 			err := item.Value(func(v []byte) error {
 				json.Unmarshal(v, &jobContext) // Convert the json back into a struct
 
-				if jobContext.Status == "RUNNING"{
-					jobContext.Status = "FAILED" // Since all the running processes wont finish, mark them failed
+				// A RUNNING job stays so, LoadRuns gives its worker the time to
+				// renew the lease on its run
+				changed := false
+				// Records written before NextRun existed only know their schedule
+				if jobContext.NextRun.IsZero() && jobContext.Job.Schedule != oneOff {
+					jobContext.NextRun = nextRun(jobContext.Job, now)
+				}
+				// Apply the misfire policy to fires missed while the server was down.
+				// Paused jobs have nothing to catch up on, resuming skips those fires anyway
+				if !jobContext.Paused && catchUp(&jobContext, now) {
+					changed = true
+				}
+				if changed {
 					jobsToFix = append(jobsToFix, jobContext)
 				}
 				store.jobs[jobContext.Job.Id] = jobContext // store the jobs in the map
+				if !jobContext.Paused {
+					scheduler.Schedule(jobContext.Job.Id, nextFire(jobContext, now))
+				}
 			return nil
 			})
 		if err != nil {
@@ -75,7 +140,7 @@ func LoadJobs(db *badger.DB) error{
 	if err != nil{
 		log.Printf("[-] Error loading jobs %v", err)
 	}
-	// Update the zombie "RUNNING" processes with "FAILED"
+	// Persist the misfire bookkeeping
 	db.Update(func(txn *badger.Txn) error {
 		for _, jobContext := range jobsToFix{
 			jsonData, err := json.Marshal(jobContext)
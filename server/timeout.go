@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// validateTimeout checks the execution timeout of a job. It is enforced by
+// the worker, which kills the container once it elapses.
+func validateTimeout(job *pb.Job) error {
+	if job.Timeout == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(job.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid timeout %q: %v", job.Timeout, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("timeout must be positive, got %s", job.Timeout)
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// workerConn is the ConnectWorker stream of a connected worker
+type workerConn struct {
+	messages chan *pb.ServerMessage // Messages to send besides jobs
+	slots    int                    // Runs the worker executes at once
+	cpus     float64                // Capacity of the worker's host, 0 = unknown and not enforced
+	memoryMb int64
+	labels   map[string]string  // Matched against the node selectors and affinities of jobs
+	busy     map[string]busyRun // Runs it holds
+	cordoned bool               // Gets no new runs
+	draining bool               // Gets no new runs and is disconnected once busy is empty
+}
+
+// busyRun is a run a worker holds and the resources it requested
+type busyRun struct {
+	at       time.Time // When it was handed to the worker
+	cpus     float64
+	memoryMb int64
+}
+
+// used returns the resources taken by the runs a worker holds. Must be
+// called with workers.mu held.
+func (c *workerConn) used() (cpus float64, memoryMb int64) {
+	for _, run := range c.busy {
+		cpus += run.cpus
+		memoryMb += run.memoryMb
+	}
+	return cpus, memoryMb
+}
+
+// room returns the resources a worker would have left after taking job, and
+// whether it can take it at all. Unknown capacity counts as unlimited. Must
+// be called with workers.mu held.
+func (c *workerConn) room(job *pb.Job) (cpus, memoryMb float64, ok bool) {
+	if !c.accepting() || len(c.busy) >= c.slots {
+		return 0, 0, false
+	}
+	usedCpus, usedMemory := c.used()
+	cpus, memoryMb = math.Inf(1), math.Inf(1)
+	if c.cpus > 0 {
+		cpus = c.cpus - usedCpus - job.Cpus
+	}
+	if c.memoryMb > 0 {
+		memoryMb = float64(c.memoryMb - usedMemory - int64(job.MemoryMb))
+	}
+	return cpus, memoryMb, cpus >= -cpuEpsilon && memoryMb >= 0
+}
+
+// accepting reports whether a worker may be handed new runs. Must be called
+// with workers.mu held.
+func (c *workerConn) accepting() bool {
+	return !c.cordoned && !c.draining
+}
+
+// matching counts the labels of a worker that have the given values
+func (c *workerConn) matching(labels map[string]string) int {
+	n := 0
+	for k, v := range labels {
+		if value, ok := c.labels[k]; ok && value == v {
+			n++
+		}
+	}
+	return n
+}
+
+// selects reports whether a worker has every label of a job's node selector
+func (c *workerConn) selects(job *pb.Job) bool {
+	return c.matching(job.NodeSelector) == len(job.NodeSelector)
+}
+
+// affinity scores how much a job prefers a worker: the labels of its affinity
+// the worker has, less those of its anti-affinity
+func (c *workerConn) affinity(job *pb.Job) int {
+	return c.matching(job.Affinity) - c.matching(job.AntiAffinity)
+}
+
+// validateResources checks the CPU and memory a job requests
+func validateResources(job *pb.Job) error {
+	if job.Cpus < 0 || math.IsNaN(job.Cpus) || math.IsInf(job.Cpus, 0) {
+		return fmt.Errorf("cpus must be a non-negative number, got %g", job.Cpus)
+	}
+	if job.MemoryMb < 0 {
+		return fmt.Errorf("memory must not be negative, got %d MB", job.MemoryMb)
+	}
+	return nil
+}
+
+// validatePlacement checks the node selector and affinities of a job
+func validatePlacement(job *pb.Job) error {
+	for what, labels := range map[string]map[string]string{
+		"node selector": job.NodeSelector,
+		"affinity":      job.Affinity,
+		"anti-affinity": job.AntiAffinity,
+	} {
+		if _, ok := labels[""]; ok {
+			return fmt.Errorf("%s has a label without a key", what)
+		}
+	}
+	for k, v := range job.Affinity {
+		if value, ok := job.AntiAffinity[k]; ok && value == v {
+			return fmt.Errorf("label %s=%s is both in the affinity and the anti-affinity", k, v)
+		}
+	}
+	return nil
+}
+
+// cpuEpsilon absorbs rounding when fractional CPU requests add up to the
+// capacity exactly
+const cpuEpsilon = 1e-9
+
+// workerConns holds the connected workers and the runs each of them holds, so
+// that a worker is only handed a run when it has a free slot
+type workerConns struct {
+	mu    sync.Mutex
+	conns map[string]*workerConn
+	freed chan struct{} // Closed and replaced whenever a slot frees up
+}
+
+var workers = workerConns{conns: make(map[string]*workerConn), freed: make(chan struct{})}
+
+// helloSlots returns the slots a worker advertised
+func helloSlots(hello *pb.WorkerHello) int {
+	if hello.Slots < 1 {
+		return 1 // Workers that do not advertise slots run one job at a time
+	}
+	return int(hello.Slots)
+}
+
+func (w *workerConns) add(hello *pb.WorkerHello, cordoned bool) *workerConn {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conn := &workerConn{
+		messages: make(chan *pb.ServerMessage, 10),
+		slots:    helloSlots(hello),
+		cpus:     hello.Cpus,
+		memoryMb: int64(hello.MemoryMb),
+		labels:   hello.Labels,
+		busy:     make(map[string]busyRun),
+		cordoned: cordoned,
+	}
+	w.conns[hello.WorkerId] = conn
+	w.notify() // Runs held back for a better fit may fit it better
+	return conn
+}
+
+// remove forgets a worker, unless it already reconnected on a new stream
+func (w *workerConns) remove(workerId string, conn *workerConn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conns[workerId] == conn {
+		delete(w.conns, workerId)
+		w.notify() // Runs held back for it can go to other workers
+	}
+}
+
+// send queues a message for a worker and reports whether it is connected
+func (w *workerConns) send(workerId string, msg *pb.ServerMessage) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conn, ok := w.conns[workerId]
+	if !ok {
+		return false
+	}
+	select {
+	case conn.messages <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// notify wakes every worker waiting for a free slot, or for the runs held
+// back for another worker. Must be called with w.mu held.
+func (w *workerConns) notify() {
+	close(w.freed)
+	w.freed = make(chan struct{})
+}
+
+// Wait returns a channel closed once a slot frees up or the workers change.
+// Take it before calling hasSlot or reserve so no wake-up is missed.
+func (w *workerConns) Wait() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.freed
+}
+
+// hasSlot reports whether a worker can take another run
+func (w *workerConns) hasSlot(conn *workerConn) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return conn.accepting() && len(conn.busy) < conn.slots
+}
+
+// cordon stops or resumes handing new runs to a worker, if it is connected
+func (w *workerConns) cordon(workerId string, cordoned bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if conn, ok := w.conns[workerId]; ok && conn.cordoned != cordoned {
+		conn.cordoned = cordoned
+		w.notify() // Runs held back for it can go to others, or it can take runs again
+	}
+}
+
+// drain stops handing new runs to a worker and has it disconnected once it
+// holds none. It reports whether the worker is connected.
+func (w *workerConns) drain(workerId string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conn, ok := w.conns[workerId]
+	if !ok {
+		return false
+	}
+	if !conn.draining {
+		conn.draining = true
+		w.notify()
+	}
+	return true
+}
+
+// isDraining reports whether a worker is connected and being drained
+func (w *workerConns) isDraining(workerId string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conn, ok := w.conns[workerId]
+	return ok && conn.draining
+}
+
+// drained reports whether a worker being drained holds no more runs
+func (w *workerConns) drained(conn *workerConn) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return conn.draining && len(conn.busy) == 0
+}
+
+// reserve takes a slot and the requested resources of a run on a worker. It
+// turns the run down if the worker lacks a label of the job's node selector
+// or the room for it, or if another worker that could take it scores higher
+// on the job's affinities. Between workers that score the same, it also turns
+// the run down if the other would be left with less room after taking it, so
+// that runs are packed onto as few workers as possible and large free blocks
+// stay available for large runs.
+func (w *workerConns) reserve(conn *workerConn, job *pb.Job) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !conn.selects(job) {
+		return false
+	}
+	cpus, memoryMb, ok := conn.room(job)
+	if !ok {
+		return false
+	}
+	packs := job.Cpus > 0 || job.MemoryMb > 0
+	if packs || len(job.Affinity) > 0 || len(job.AntiAffinity) > 0 {
+		score := conn.affinity(job)
+		for _, other := range w.conns {
+			if other == conn || !other.selects(job) {
+				continue
+			}
+			otherCpus, otherMemory, fits := other.room(job)
+			if !fits {
+				continue
+			}
+			otherScore := other.affinity(job)
+			if otherScore > score {
+				return false // Left for the preferred worker
+			}
+			if packs && otherScore == score && (otherMemory < memoryMb || otherMemory == memoryMb && otherCpus < cpus) {
+				return false // Left for the tighter fit
+			}
+		}
+	}
+	conn.busy[job.RunId] = busyRun{at: time.Now(), cpus: job.Cpus, memoryMb: int64(job.MemoryMb)}
+	w.notify() // Runs this worker turned down for a tighter fit may now be left to others
+	return true
+}
+
+// holding returns the runs a worker holds and the resources they requested
+func (w *workerConns) holding(workerId string) (runIds []string, cpus float64, memoryMb int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conn, ok := w.conns[workerId]
+	if !ok {
+		return nil, 0, 0
+	}
+	for runId := range conn.busy {
+		runIds = append(runIds, runId)
+	}
+	sort.Strings(runIds)
+	cpus, memoryMb = conn.used()
+	return runIds, cpus, memoryMb
+}
+
+// release frees the slot a run held, because it finished or was taken back
+func (w *workerConns) release(runId string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	freed := false
+	for _, conn := range w.conns {
+		if _, ok := conn.busy[runId]; ok {
+			delete(conn.busy, runId)
+			freed = true
+		}
+	}
+	if freed {
+		w.notify()
+	}
+}
+
+// releaseOn frees the slot a run held on one worker only, e.g. a stale copy
+// of a run another worker holds by now
+func (w *workerConns) releaseOn(workerId, runId string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conn, ok := w.conns[workerId]
+	if !ok {
+		return
+	}
+	if _, ok := conn.busy[runId]; ok {
+		delete(conn.busy, runId)
+		w.notify()
+	}
+}
+
+// sync matches the runs a worker holds with those listed in its heartbeat.
+// Runs handed to it within the visibility timeout may not be listed yet and
+// are kept; runs it no longer has, e.g. dropped before it acked them, free
+// their slot, and runs it kept across a reconnect take one, without counting
+// their resources, which are not known here.
+func (w *workerConns) sync(conn *workerConn, held []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	before := len(conn.busy)
+	busy := make(map[string]busyRun, len(held))
+	now := time.Now()
+	for runId, run := range conn.busy {
+		if now.Sub(run.at) < visibilityTimeout {
+			busy[runId] = run
+		}
+	}
+	for _, runId := range held {
+		if _, ok := busy[runId]; !ok {
+			busy[runId] = conn.busy[runId]
+		}
+	}
+	conn.busy = busy
+	if len(busy) < before {
+		w.notify()
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// testWorker describes a connected worker and the runs it already holds
+type testWorker struct {
+	slots    int32
+	cpus     float64
+	memoryMb int32
+	labels   map[string]string
+	held     []busyRun
+	cordoned bool
+	draining bool
+}
+
+func newTestWorkers(specs map[string]testWorker) *workerConns {
+	w := &workerConns{conns: make(map[string]*workerConn), freed: make(chan struct{})}
+	for id, spec := range specs {
+		conn := w.add(&pb.WorkerHello{WorkerId: id, Slots: spec.slots, Cpus: spec.cpus, MemoryMb: spec.memoryMb, Labels: spec.labels}, spec.cordoned)
+		conn.draining = spec.draining
+		for i, run := range spec.held {
+			conn.busy[id+"-held-"+string(rune('a'+i))] = run
+		}
+	}
+	return w
+}
+
+func TestWorkersReserve(t *testing.T) {
+	big := testWorker{slots: 4, cpus: 8, memoryMb: 8192}
+	small := testWorker{slots: 4, cpus: 2, memoryMb: 1024}
+
+	tests := []struct {
+		name    string
+		workers map[string]testWorker
+		job     *pb.Job
+		on      string
+		want    bool
+	}{
+		{"free slot", map[string]testWorker{"a": {}}, &pb.Job{}, "a", true},
+		{"no free slot", map[string]testWorker{"a": {held: []busyRun{{}}}}, &pb.Job{}, "a", false},
+		{"all slots", map[string]testWorker{"a": {slots: 3, held: []busyRun{{}, {}}}}, &pb.Job{}, "a", true},
+		{"cordoned", map[string]testWorker{"a": {cordoned: true}}, &pb.Job{}, "a", false},
+		{"draining", map[string]testWorker{"a": {draining: true}}, &pb.Job{}, "a", false},
+		{"unknown capacity is not enforced", map[string]testWorker{"a": {}}, &pb.Job{Cpus: 64, MemoryMb: 1 << 20}, "a", true},
+		{"not enough memory", map[string]testWorker{"a": small}, &pb.Job{MemoryMb: 2048}, "a", false},
+		{"not enough cpus", map[string]testWorker{"a": small}, &pb.Job{Cpus: 2.5}, "a", false},
+		{"memory taken by held runs", map[string]testWorker{"a": {slots: 4, memoryMb: 1024, held: []busyRun{{memoryMb: 768}}}}, &pb.Job{MemoryMb: 512}, "a", false},
+		{"fractional cpus add up to the capacity", map[string]testWorker{"a": {slots: 4, cpus: 1, held: []busyRun{{cpus: 0.1}, {cpus: 0.2}}}}, &pb.Job{Cpus: 0.7}, "a", true},
+
+		// Packing
+		{"left for the tighter fit", map[string]testWorker{"big": big, "small": small}, &pb.Job{MemoryMb: 512}, "big", false},
+		{"tighter fit", map[string]testWorker{"big": big, "small": small}, &pb.Job{MemoryMb: 512}, "small", true},
+		{"same memory, tighter on cpus", map[string]testWorker{"a": {slots: 4, cpus: 8, memoryMb: 1024}, "b": {slots: 4, cpus: 2, memoryMb: 1024}}, &pb.Job{Cpus: 1}, "a", false},
+		{"held runs make the tighter fit", map[string]testWorker{"big": {slots: 4, memoryMb: 8192, held: []busyRun{{memoryMb: 7680}}}, "small": small}, &pb.Job{MemoryMb: 256}, "small", false},
+		{"tighter worker too small", map[string]testWorker{"big": big, "small": small}, &pb.Job{MemoryMb: 2048}, "big", true},
+		{"tighter worker full", map[string]testWorker{"big": big, "small": {slots: 1, cpus: 2, memoryMb: 1024, held: []busyRun{{}}}}, &pb.Job{MemoryMb: 512}, "big", true},
+		{"tighter worker cordoned", map[string]testWorker{"big": big, "small": {slots: 4, cpus: 2, memoryMb: 1024, cordoned: true}}, &pb.Job{MemoryMb: 512}, "big", true},
+		{"no packing without requests", map[string]testWorker{"big": big, "small": small}, &pb.Job{}, "big", true},
+
+		// Node selectors
+		{"selector matches", map[string]testWorker{"a": {labels: map[string]string{"disk": "ssd", "zone": "a"}}}, &pb.Job{NodeSelector: map[string]string{"disk": "ssd"}}, "a", true},
+		{"selector value differs", map[string]testWorker{"a": {labels: map[string]string{"disk": "hdd"}}}, &pb.Job{NodeSelector: map[string]string{"disk": "ssd"}}, "a", false},
+		{"selector label missing", map[string]testWorker{"a": {}}, &pb.Job{NodeSelector: map[string]string{"disk": "ssd"}}, "a", false},
+		{"every selector label is needed", map[string]testWorker{"a": {labels: map[string]string{"disk": "ssd"}}}, &pb.Job{NodeSelector: map[string]string{"disk": "ssd", "gpu": "yes"}}, "a", false},
+		{"tighter worker not selected", map[string]testWorker{"big": {slots: 4, memoryMb: 8192, labels: map[string]string{"disk": "ssd"}}, "small": small}, &pb.Job{MemoryMb: 512, NodeSelector: map[string]string{"disk": "ssd"}}, "big", true},
+
+		// Affinities
+		{"left for the preferred worker", map[string]testWorker{"a": {labels: map[string]string{"zone": "a"}}, "b": {labels: map[string]string{"zone": "b"}}}, &pb.Job{Affinity: map[string]string{"zone": "b"}}, "a", false},
+		{"preferred worker", map[string]testWorker{"a": {labels: map[string]string{"zone": "a"}}, "b": {labels: map[string]string{"zone": "b"}}}, &pb.Job{Affinity: map[string]string{"zone": "b"}}, "b", true},
+		{"preferred worker busy", map[string]testWorker{"a": {labels: map[string]string{"zone": "a"}}, "b": {labels: map[string]string{"zone": "b"}, held: []busyRun{{}}}}, &pb.Job{Affinity: map[string]string{"zone": "b"}}, "a", true},
+		{"anti-affinity", map[string]testWorker{"a": {labels: map[string]string{"zone": "a"}}, "b": {labels: map[string]string{"zone": "b"}}}, &pb.Job{AntiAffinity: map[string]string{"zone": "a"}}, "a", false},
+		{"avoided worker is the only one", map[string]testWorker{"a": {labels: map[string]string{"zone": "a"}}}, &pb.Job{AntiAffinity: map[string]string{"zone": "a"}}, "a", true},
+		{"affinity before packing", map[string]testWorker{"big": {slots: 4, memoryMb: 8192, labels: map[string]string{"zone": "a"}}, "small": small}, &pb.Job{MemoryMb: 512, Affinity: map[string]string{"zone": "a"}}, "big", true},
+		{"packing between equal scores", map[string]testWorker{"big": {slots: 4, memoryMb: 8192, labels: map[string]string{"zone": "a"}}, "small": {slots: 4, memoryMb: 1024, labels: map[string]string{"zone": "a"}}}, &pb.Job{MemoryMb: 512, Affinity: map[string]string{"zone": "a"}}, "big", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestWorkers(tt.workers)
+			conn := w.conns[tt.on]
+			tt.job.RunId = "run"
+			before := len(conn.busy)
+			if got := w.reserve(conn, tt.job); got != tt.want {
+				t.Fatalf("reserve on %s = %v, want %v", tt.on, got, tt.want)
+			}
+			if _, ok := conn.busy["run"]; ok != tt.want || len(conn.busy) != before+btoi(tt.want) {
+				t.Errorf("worker %s holds %d runs after reserve = %v, had %d", tt.on, len(conn.busy), tt.want, before)
+			}
+		})
+	}
+}
+
+// TestWorkersReserveRelease fills a worker up and frees it again
+func TestWorkersReserveRelease(t *testing.T) {
+	w := newTestWorkers(map[string]testWorker{"a": {slots: 2, cpus: 2, memoryMb: 1024}})
+	conn := w.conns["a"]
+	if !w.reserve(conn, &pb.Job{RunId: "1", MemoryMb: 512}) || !w.reserve(conn, &pb.Job{RunId: "2", MemoryMb: 512}) {
+		t.Fatal("reserve turned down runs that fit")
+	}
+	if w.reserve(conn, &pb.Job{RunId: "3"}) {
+		t.Fatal("reserve took a run without a free slot")
+	}
+	runIds, _, memoryMb := w.holding("a")
+	if len(runIds) != 2 || memoryMb != 1024 {
+		t.Fatalf("holding = %v, %d MB, want 2 runs, 1024 MB", runIds, memoryMb)
+	}
+
+	freed := w.Wait()
+	w.release("1")
+	select {
+	case <-freed:
+	default:
+		t.Error("release did not wake waiting workers")
+	}
+	if w.reserve(conn, &pb.Job{RunId: "3", MemoryMb: 1024}) {
+		t.Error("reserve took a run larger than the memory left")
+	}
+	if !w.reserve(conn, &pb.Job{RunId: "3", MemoryMb: 512}) {
+		t.Error("reserve turned down a run after a slot was released")
+	}
+}
+
+func TestValidatePlacement(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     *pb.Job
+		wantErr bool
+	}{
+		{"none", &pb.Job{}, false},
+		{"all kinds", &pb.Job{NodeSelector: map[string]string{"disk": "ssd"}, Affinity: map[string]string{"zone": "a"}, AntiAffinity: map[string]string{"zone": "b"}}, false},
+		{"selector without key", &pb.Job{NodeSelector: map[string]string{"": "ssd"}}, true},
+		{"anti-affinity without key", &pb.Job{AntiAffinity: map[string]string{"": "b"}}, true},
+		{"same label preferred and avoided", &pb.Job{Affinity: map[string]string{"zone": "a"}, AntiAffinity: map[string]string{"zone": "a"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePlacement(tt.job); (err != nil) != tt.wantErr {
+				t.Errorf("validatePlacement() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
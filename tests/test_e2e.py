@@ -127,3 +127,29 @@ def test_cron_job():
 
     assert out.returncode == 0
     assert len(matches) >= 2
+
+def test_delayed_one_off_job():
+    out = subprocess.run(
+        ["./bin/client", "submit", "-i", "alpine", "-c",
+         "echo Hello from test_delayed_one_off_job", "--in", "10s"],
+        capture_output=True, text=True, check=True,
+        cwd=PROJECT_ROOT,
+    )
+    job_id = extract_job_id(out.stderr)
+
+    out = subprocess.run(
+        ["./bin/client", "status", "-j", str(job_id)],
+        capture_output=True, text=True, check=True,
+        cwd=PROJECT_ROOT,
+    )
+    assert "Hello from test_delayed_one_off_job" not in out.stderr
+
+    time.sleep(20)
+
+    out = subprocess.run(
+        ["./bin/client", "status", "-j", str(job_id)],
+        capture_output=True, text=True, check=True,
+        cwd=PROJECT_ROOT,
+    )
+    assert out.returncode == 0
+    assert "Hello from test_delayed_one_off_job" in out.stderr
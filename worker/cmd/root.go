@@ -7,9 +7,17 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"log"
 	"encoding/json"
 	"encoding/base64"
+	"errors"
+	"strings"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 	"github.com/spf13/cobra"
 
 	pb "github.com/dhaval314/epoch/proto"
@@ -29,6 +37,20 @@ var key string
 var target string
 
 var WorkerId string
+var Slots int32
+var Cpus float64
+var MemoryMb int32
+var Labels []string
+
+// Version is the build of the worker, reported to the server. Set it with
+// -ldflags "-X github.com/dhaval314/epoch/worker/cmd.Version=v1.2.0"
+var Version = "dev"
+
+// heartbeatInterval is how often the worker renews the leases of its runs
+const heartbeatInterval = 5 * time.Second
+
+// errTimedOut is returned by executeCommand when the job's timeout elapsed
+var errTimedOut = errors.New("run timed out")
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -61,17 +83,56 @@ func init() {
 	// when this action is called directly.
 	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
+	rootCmd.Version = Version
 	rootCmd.Flags().StringVarP(&WorkerId, "worker-id", "i", "0", "Specify the worker id")
+	rootCmd.Flags().Int32VarP(&Slots, "slots", "n", 1, "Number of jobs to run at once")
+	rootCmd.Flags().Float64Var(&Cpus, "cpus", 0, "CPUs to offer for jobs (default: all CPUs of the Docker host)")
+	rootCmd.Flags().Int32Var(&MemoryMb, "memory-mb", 0, "Memory to offer for jobs, in MB (default: all memory of the Docker host)")
+	rootCmd.Flags().StringArrayVarP(&Labels, "label", "l", nil, "Label as key=value for job node selectors and affinities, can be repeated")
+}
+
+// hostCapacity returns the CPUs and memory the worker offers, detected from
+// the Docker daemon unless set with --cpus and --memory-mb. What cannot be
+// detected is reported as 0, which the server does not enforce.
+func hostCapacity() (float64, int32) {
+	cpus, memoryMb := Cpus, MemoryMb
+	if cpus > 0 && memoryMb > 0 {
+		return cpus, memoryMb
+	}
+	apiClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		log.Printf("[-] Error creating client: %v\n", err)
+		return cpus, memoryMb
+	}
+	defer apiClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	info, err := apiClient.Info(ctx)
+	if err != nil {
+		log.Printf("[-] Error reading Docker host capacity, not advertising it: %v", err)
+		return cpus, memoryMb
+	}
+	if cpus <= 0 {
+		cpus = float64(info.NCPU)
+	}
+	if memoryMb <= 0 {
+		memoryMb = int32(info.MemTotal / (1024 * 1024))
+	}
+	return cpus, memoryMb
 }
 
-func executeCommand(ctx context.Context, req *pb.Job)(string, error){
+// executeCommand runs a job in a container and returns the result to report,
+// with exit code -1 if the container did not run to completion
+func executeCommand(ctx context.Context, req *pb.Job)(*pb.JobResult, error){
+	result := &pb.JobResult{JobId: req.Id, RunId: req.RunId, ExitCode: -1}
 
 	// NOTE: client.NewClientWithOpts is Deprecated, but the new version (client.New()) doesnt work because of dependency issues
 	// Create client 
 	apiClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err!= nil{
 		log.Printf("[-] Error creating client: %v\n", err)
-		return "", err
+		return result, err
 	}
 	defer apiClient.Close()
 	
@@ -95,7 +156,7 @@ func executeCommand(ctx context.Context, req *pb.Job)(string, error){
 	reader, err := apiClient.ImagePull(ctx, req.Image, image.PullOptions{RegistryAuth: encoded_auth})
 	if err!= nil{
 		log.Printf("[-] Error pulling container: %v\n", err)
-		return "", err
+		return result, err
 	}
 	defer reader.Close()
 	
@@ -105,37 +166,139 @@ func executeCommand(ctx context.Context, req *pb.Job)(string, error){
 	resp, err := apiClient.ContainerCreate(ctx, &container.Config{
 		Cmd:   []string{"sh","-c", req.Command},
 		Image: req.Image,
-	}, nil, nil, nil, "")
+	}, hostConfig(req.Limits), nil, nil, "")
 	if err != nil{
 		log.Printf("[-] Error creating container: %v\n", err)
-		return "", err
+		return result, err
 	}
 	log.Printf("[+] Created container with Id: %v\n", resp.ID)
 
+	// The server cancelled the run: stop and remove the container, whatever state it got to
+	defer func() {
+		if ctx.Err() != nil {
+			removeContainer(apiClient, resp.ID)
+		}
+	}()
+
 	// Start the container
 	err = apiClient.ContainerStart(ctx, resp.ID, container.StartOptions{})
 	if err != nil{
 		log.Printf("[-] Error starting container: %v\n", err)
-		return "", err
+		return result, err
 	}
 	log.Printf("[+] Started container with Id: %v\n", resp.ID)
 
-	statusCh, errCh := apiClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	// The job's timeout counts from the start of the container
+	waitCtx := ctx
+	if timeout, err := time.ParseDuration(req.Timeout); err == nil && timeout > 0 {
+		var stop context.CancelFunc
+		waitCtx, stop = context.WithTimeout(ctx, timeout)
+		defer stop()
+	}
+
+	statusCh, errCh := apiClient.ContainerWait(waitCtx, resp.ID, container.WaitConditionNotRunning)
 
 	// Wait for the container to finish
 	select{
 	case err := <-errCh:
+		if ctx.Err() != nil {
+			log.Printf("[*] Run %s cancelled, stopping container %v", req.RunId, resp.ID)
+			result.Output, _ = containerOutput(context.Background(), apiClient, resp.ID) // Whatever it printed before being stopped
+			return result, ctx.Err()
+		}
+		if waitCtx.Err() != nil {
+			log.Printf("[-] Run %s timed out after %s, killing container %v", req.RunId, req.Timeout, resp.ID)
+			result.Output, _ = containerOutput(context.Background(), apiClient, resp.ID)
+			removeContainer(apiClient, resp.ID)
+			result.TimedOut = true
+			result.Reason = "timed out after " + req.Timeout
+			return result, errTimedOut
+		}
 		if err !=nil{
 			log.Printf("[-] Error waiting: %v", err)
-            return "", err
+            return result, err
 		}
-	case <-statusCh:
+	case status := <-statusCh:
 		// Job is done
+		result.ExitCode = int32(status.StatusCode)
+	}
+	inspectContainer(ctx, apiClient, resp.ID, result)
+	log.Printf("[+] Executed container with Id: %v (exit code %d)\n", resp.ID, result.ExitCode)
+
+	// The container ran to completion, so its exit code decides the outcome
+	// even if its output cannot be fetched
+	if result.Output, err = containerOutput(ctx, apiClient, resp.ID); err != nil {
+		result.Output = fmt.Sprintf("[-] Could not fetch the output of container %v: %v", resp.ID, err)
+	}
+	return result, nil
+}
+
+// inspectContainer fills in how a container that stopped ended: its exit
+// code, whether it ran out of memory or was killed by a signal, and when it
+// started and finished
+func inspectContainer(ctx context.Context, apiClient *client.Client, containerId string, result *pb.JobResult){
+	info, err := apiClient.ContainerInspect(ctx, containerId)
+	if err != nil || info.ContainerJSONBase == nil || info.State == nil {
+		log.Printf("[-] Error inspecting container %v: %v", containerId, err)
+		return
+	}
+	state := info.State
+	result.ExitCode = int32(state.ExitCode)
+	result.OomKilled = state.OOMKilled
+	switch {
+	case state.OOMKilled:
+		result.Reason = "OOMKilled"
+	case state.ExitCode > 128: // Shells report death by signal N as 128+N
+		result.Reason = fmt.Sprintf("killed by signal %d%s", state.ExitCode-128, signalName(state.ExitCode-128))
+	case state.Error != "":
+		result.Reason = state.Error
+	}
+	if t, err := time.Parse(time.RFC3339Nano, state.StartedAt); err == nil && !t.IsZero() {
+		result.StartedAt = t.Unix()
+	}
+	if t, err := time.Parse(time.RFC3339Nano, state.FinishedAt); err == nil && !t.IsZero() {
+		result.FinishedAt = t.Unix()
+	}
+}
+
+// signalName returns " (SIGNAME)" for the common signals, "" for others
+func signalName(sig int) string {
+	names := map[int]string{1: "SIGHUP", 2: "SIGINT", 3: "SIGQUIT", 6: "SIGABRT", 9: "SIGKILL", 11: "SIGSEGV", 13: "SIGPIPE", 15: "SIGTERM"}
+	if name, ok := names[sig]; ok {
+		return " (" + name + ")"
+	}
+	return ""
+}
+
+// hostConfig turns the limits of a job into the HostConfig of its container
+func hostConfig(limits *pb.ResourceLimits) *container.HostConfig {
+	if limits == nil {
+		return nil
+	}
+	const mb = 1024 * 1024
+	resources := container.Resources{
+		Memory:    limits.MemoryMb * mb,
+		NanoCPUs:  int64(limits.Cpus * 1e9),
+		CPUShares: limits.CpuShares,
+	}
+	if limits.MemorySwapMb > 0 {
+		resources.MemorySwap = limits.MemorySwapMb * mb
+	} else {
+		resources.MemorySwap = limits.MemorySwapMb // -1 = unlimited, 0 = Docker's default
+	}
+	if limits.Pids > 0 {
+		resources.PidsLimit = &limits.Pids
+	}
+	for _, u := range limits.Ulimits {
+		resources.Ulimits = append(resources.Ulimits, &container.Ulimit{Name: u.Name, Soft: u.Soft, Hard: u.Hard})
 	}
-	log.Printf("[+] Executed container with Id: %v\n", resp.ID)
+	return &container.HostConfig{Resources: resources}
+}
 
+// containerOutput returns what a container wrote to stdout and stderr
+func containerOutput(ctx context.Context, apiClient *client.Client, containerId string)(string, error){
 	// Get the output from the container
-	out, err := apiClient.ContainerLogs(ctx, resp.ID, container.LogsOptions{ShowStdout: true})
+	out, err := apiClient.ContainerLogs(ctx, containerId, container.LogsOptions{ShowStdout: true})
     if err != nil {
         log.Printf("[-] Error getting logs: %v", err)
         return "", err
@@ -153,7 +316,34 @@ func executeCommand(ctx context.Context, req *pb.Job)(string, error){
 	return bodyString, nil
 }
 
+// removeContainer stops a container and removes it
+func removeContainer(apiClient *client.Client, containerId string){
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	timeout := 5 // Seconds to exit after SIGTERM before it is killed
+	if err := apiClient.ContainerStop(ctx, containerId, container.StopOptions{Timeout: &timeout}); err != nil {
+		log.Printf("[-] Error stopping container %v: %v", containerId, err)
+	}
+	if err := apiClient.ContainerRemove(ctx, containerId, container.RemoveOptions{Force: true}); err != nil {
+		log.Printf("[-] Error removing container %v: %v", containerId, err)
+		return
+	}
+	log.Printf("[+] Removed container with Id: %v\n", containerId)
+}
+
 func connectWorker(cmd *cobra.Command, args[] string){
+	if Slots < 1 {
+		log.Fatalf("[-] --slots must be at least 1, got %d", Slots)
+	}
+	labels := map[string]string{}
+	for _, label := range Labels {
+		k, v, ok := strings.Cut(label, "=")
+		if !ok || k == "" {
+			log.Fatalf("[-] Invalid label %q, want key=value", label)
+		}
+		labels[k] = v
+	}
 
 	// Generate the certificate from the pem blocks
 	cert, err := tls.LoadX509KeyPair(cert, key)
@@ -182,42 +372,117 @@ func connectWorker(cmd *cobra.Command, args[] string){
 		log.Fatalf("[-] Error connecting to server: %v\n", err)
 	}
 	defer conn.Close()
-	log.Println("[+] Successfully Connected to the server")
 
 	client := pb.NewSchedulerClient(conn)
-	ctx:= context.Background()
+	cpus, memoryMb := hostCapacity()
+	log.Printf("[*] Offering %d slots, %g CPUs and %d MB of memory", Slots, cpus, memoryMb)
+	hello := &pb.WorkerHello{WorkerId: WorkerId, MemoryMb: memoryMb, Slots: Slots, Cpus: cpus, Labels: labels, Version: Version} // Get the cmd.workerid from parsed the flag
 
-	stream, err := client.ConnectWorker(ctx, &pb.WorkerHello{WorkerId: WorkerId, MemoryMb: 2}) // Get the cmd.workerid from parsed the flag
-	if err != nil{
-		log.Fatalf("[-] Error connecting to server: %v\n", err)
+	// Runs execute on their own goroutines and report their results with
+	// CompleteJob, so they carry on while the stream is down
+	runs := newRunContexts()
+	jobs := make(chan queuedRun, 100)
+	var executing sync.WaitGroup
+	for range Slots {
+		executing.Add(1)
+		go func() {
+			defer executing.Done()
+			runJobs(client, runs, jobs)
+		}()
 	}
-	for{
-		job, err := stream.Recv()
-		if err != nil{
-			log.Printf("[-] Error recieving job: %v\n", err)
+	current := &serverStream{}
+
+	// SIGTERM or an interrupt drains the worker instead of killing its runs:
+	// the server sends it no more and disconnects it once they are finished.
+	// A second signal stops it at once.
+	var draining atomic.Bool
+	drainStarted := make(chan struct{})
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		<-signals
+		draining.Store(true)
+		close(drainStarted)
+		log.Printf("[*] Draining, waiting for %d runs to finish; signal again to stop at once", runs.count())
+		if err := current.send(&pb.WorkerMessage{Message: &pb.WorkerMessage_Drain{Drain: &pb.Drain{}}}); err != nil && err != errNotConnected {
+			log.Printf("[-] Error telling the server to drain the worker: %v", err)
+		}
+		<-signals
+		log.Fatalln("[-] Stopped before the runs finished")
+	}()
+
+	// A broken stream is opened again, so the runs the worker holds keep their
+	// leases and their results are still counted
+	wait := reconnectMin
+	for {
+		started := time.Now()
+		err := serve(client, current, hello, runs, jobs, draining.Load())
+		if err == nil {
+			break // Drained by the server
+		}
+		if draining.Load() && runs.count() == 0 {
+			break // Nothing left to finish
+		}
+		if time.Since(started) > reconnectMax {
+			wait = reconnectMin
+		}
+		log.Printf("[-] Lost the connection to the server: %v, reconnecting in %s", err, wait)
+		select {
+		case <-time.After(wait):
+		case <-drainStarted:
+			drainStarted = nil
+		}
+		if draining.Load() && runs.count() == 0 {
 			break
 		}
-		output, err := executeCommand(context.Background(), job)
-		if err != nil{
-			_, err := client.CompleteJob(context.Background(), &pb.JobResult{
-																	JobId: job.Id, 
-																	Success: false,
-																	Output: output,})
-			if err != nil{
-				log.Printf("[-] Error sending job result to server")
-			} else{
-				log.Printf("[+] Sent job result to server")
+		wait = min(wait*2, reconnectMax)
+	}
+
+	close(jobs)
+	if n := runs.count(); n > 0 {
+		log.Printf("[*] Waiting for %d runs to finish", n)
+	}
+	executing.Wait()
+}
+
+// heartbeat tells the server which runs the worker holds, renewing their
+// leases, until ctx is cancelled
+func heartbeat(ctx context.Context, runs *runContexts, send func(*pb.WorkerMessage) error){
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			err := send(&pb.WorkerMessage{Message: &pb.WorkerMessage_Heartbeat{Heartbeat: &pb.Heartbeat{RunIds: runs.held(now)}}})
+			if err != nil {
+				log.Printf("[-] Error sending heartbeat: %v", err)
+				return
 			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runJobs executes the jobs received from the server and reports their results
+func runJobs(client pb.SchedulerClient, runs *runContexts, jobs <-chan queuedRun){
+	for queued := range jobs {
+		job := queued.job
+		result, err := executeCommand(queued.ctx, job)
+		if !runs.done(queued) {
+			log.Printf("[*] Lease of run %s lapsed, the server re-queued it", job.RunId)
+			continue
+		}
+		if err != nil && result.Reason == "" {
+			result.Reason = err.Error() // Why the container could not be run
+		}
+		result.Success = err == nil && result.ExitCode == 0 && !result.OomKilled
+		result.WorkerId = WorkerId
+		_, err = client.CompleteJob(context.Background(), result)
+		if err != nil{
+			log.Printf("[-] Error sending job result to server")
 		} else{
-			_, err := client.CompleteJob(context.Background(), &pb.JobResult{
-																	JobId: job.Id, 
-																	Success: true,
-																	Output: output,})
-			if err != nil{
-				log.Printf("[-] Error sending job result to server")
-			} else{
-				log.Printf("[+] Sent job result to server")
-			}
+			log.Printf("[+] Sent job result to server")
 		}
 	}
-}
\ No newline at end of file
+}
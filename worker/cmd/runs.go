@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+)
+
+// queuedRun is a job received from the server along with the context it runs in
+type queuedRun struct {
+	job  *pb.Job
+	ctx  context.Context
+	held *heldRun
+}
+
+// heldRun is a run the worker has received and not finished yet
+type heldRun struct {
+	cancel context.CancelFunc
+	lease  time.Time // When the server takes the run back unless it is renewed, zero until granted
+	lapsed bool      // The server took the run back, its result is not reported
+}
+
+// runContexts holds a cancellable context per run the worker has received,
+// so the server can cancel a run before or while it executes, along with the
+// lease the server granted on it
+type runContexts struct {
+	mu   sync.Mutex
+	runs map[string]*heldRun
+}
+
+func newRunContexts() *runContexts {
+	return &runContexts{runs: make(map[string]*heldRun)}
+}
+
+// start registers a run received from the server. A run the worker still
+// holds is sent again after its lease lapsed, so the stale copy is cancelled.
+func (r *runContexts) start(job *pb.Job) queuedRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stale, ok := r.runs[job.RunId]; ok {
+		stale.lapsed = true
+		stale.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	held := &heldRun{cancel: cancel}
+	r.runs[job.RunId] = held
+	return queuedRun{job: job, ctx: ctx, held: held}
+}
+
+// done releases the context of a finished run and reports whether its
+// result should be sent to the server. A lease that ran out while the worker
+// was cut off from the server counts as lapsed, as no heartbeat noticed it.
+func (r *runContexts) done(run queuedRun) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run.held.cancel()
+	if r.runs[run.job.RunId] == run.held {
+		delete(r.runs, run.job.RunId)
+	}
+	if !run.held.lease.IsZero() && time.Now().After(run.held.lease) {
+		run.held.lapsed = true
+	}
+	return !run.held.lapsed
+}
+
+// cancel cancels a run and reports whether the worker holds it
+func (r *runContexts) cancel(runId string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	held, ok := r.runs[runId]
+	if ok {
+		held.cancel()
+	}
+	return ok
+}
+
+// lease records the lease the server granted on a run
+func (r *runContexts) lease(runId string, expires time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if held, ok := r.runs[runId]; ok {
+		held.lease = expires
+	}
+}
+
+// held returns the runs the worker holds and cancels those whose lease
+// lapsed, since the server has given them to another worker by now
+func (r *runContexts) held(now time.Time) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runIds := []string{}
+	for runId, held := range r.runs {
+		if !held.lease.IsZero() && now.After(held.lease) {
+			held.lapsed = true
+			held.cancel()
+			continue
+		}
+		runIds = append(runIds, runId)
+	}
+	return runIds
+}
+
+// count returns how many runs the worker holds
+func (r *runContexts) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.runs)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/dhaval314/epoch/proto"
+	"google.golang.org/grpc"
+)
+
+// How long the worker waits before connecting to the server again, doubled
+// after every failed attempt
+const (
+	reconnectMin = 1 * time.Second
+	reconnectMax = 30 * time.Second
+)
+
+var errNotConnected = errors.New("not connected to the server")
+
+// serverStream is the current ConnectWorker stream, replaced on every
+// reconnect. Acks, heartbeats and drains are sent from different goroutines,
+// so sends are serialized.
+type serverStream struct {
+	mu     sync.Mutex
+	stream grpc.BidiStreamingClient[pb.WorkerMessage, pb.ServerMessage]
+}
+
+func (s *serverStream) set(stream grpc.BidiStreamingClient[pb.WorkerMessage, pb.ServerMessage]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stream = stream
+}
+
+func (s *serverStream) send(msg *pb.WorkerMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream == nil {
+		return errNotConnected
+	}
+	return s.stream.Send(msg)
+}
+
+// serve opens a ConnectWorker stream and handles what the server sends down
+// it until it breaks. The worker introduces itself with hello and at once
+// lists the runs it still holds from an earlier stream, renewing their
+// leases before they lapse. It returns nil once the server disconnected the
+// worker after draining it.
+func serve(client pb.SchedulerClient, current *serverStream, hello *pb.WorkerHello, runs *runContexts, jobs chan<- queuedRun, draining bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.ConnectWorker(ctx)
+	if err != nil {
+		return err
+	}
+	current.set(stream)
+	defer current.set(nil)
+
+	if err := current.send(&pb.WorkerMessage{Message: &pb.WorkerMessage_Hello{Hello: hello}}); err != nil {
+		return err
+	}
+	if held := runs.held(time.Now()); len(held) > 0 {
+		log.Printf("[*] Still holding %d runs, renewing their leases", len(held))
+		if err := current.send(&pb.WorkerMessage{Message: &pb.WorkerMessage_Heartbeat{Heartbeat: &pb.Heartbeat{RunIds: held}}}); err != nil {
+			return err
+		}
+	}
+	if draining {
+		if err := current.send(&pb.WorkerMessage{Message: &pb.WorkerMessage_Drain{Drain: &pb.Drain{}}}); err != nil {
+			return err
+		}
+	}
+	log.Println("[+] Successfully Connected to the server")
+	go heartbeat(ctx, runs, current.send)
+
+	// Up to Slots runs execute at once, while this loop keeps reading so a
+	// cancel reaches a run that is executing or still waiting its turn
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		switch m := msg.Message.(type) {
+		case *pb.ServerMessage_Job:
+			jobs <- runs.start(m.Job)
+			if err := current.send(&pb.WorkerMessage{Message: &pb.WorkerMessage_Ack{Ack: &pb.Ack{RunId: m.Job.RunId}}}); err != nil {
+				log.Printf("[-] Error acking run %s: %v", m.Job.RunId, err)
+			}
+		case *pb.ServerMessage_Cancel:
+			if runs.cancel(m.Cancel.RunId) {
+				log.Printf("[*] Cancelling run %s", m.Cancel.RunId)
+			}
+		case *pb.ServerMessage_Leases:
+			for _, lease := range m.Leases.Leases {
+				runs.lease(lease.RunId, time.Unix(lease.ExpiresAt, 0))
+			}
+		case *pb.ServerMessage_Disconnect:
+			log.Printf("[+] Disconnecting, the server %s the worker", m.Disconnect.Reason)
+			stream.CloseSend()
+			return nil
+		}
+	}
+}